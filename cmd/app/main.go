@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/Ross1116/coder-copy/pkg/config"
+	"github.com/Ross1116/coder-copy/pkg/languages"
 	"github.com/Ross1116/coder-copy/pkg/monitor"
 	"golang.design/x/clipboard"
 )
@@ -16,15 +17,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := languages.LoadUserConfig(); err != nil {
+		fmt.Println("Warning: custom languages not loaded:", err)
+	}
+
 	cfg := config.GetConfig()
 	if cfg != nil {
 		fmt.Println("Clipboard monitor started, Press ctrl+C to exit")
-		monitor.MonitorClipboard(cfg.Language, cfg.Format)
+		monitor.MonitorClipboard(cfg)
 		return
 	}
 
-	processContentFn := func(content, language string, format bool) (string, error) {
-		return monitor.ProcessContent(content, language, format)
+	processContentFn := func(content string, cfg *config.Config) (config.Result, error) {
+		return monitor.Process(content, cfg)
 	}
 
 	p := config.NewProgram(processContentFn)
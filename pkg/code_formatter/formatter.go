@@ -5,13 +5,15 @@ import (
 	"go/format"
 	"os/exec"
 	"strings"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
 )
 
 type Language string
 
 const (
 	Go     Language = "go"
-	CPP    Language = "cpp"
+	CPP    Language = "c"
 	Java   Language = "java"
 	JS     Language = "javascript"
 	TS     Language = "typescript"
@@ -20,76 +22,39 @@ const (
 	Python Language = "python"
 )
 
-func FormatCode(code string, lang Language) (string, error) {
-	switch lang {
-	case Go:
-		return formatGo(code)
-	case CPP:
-		return formatCPP(code)
-	case Java:
-		return formatJava(code)
-	case JS, TS:
-		return formatJavaScript(code, "babel")
-	case JSX:
-		return formatJavaScript(code, "babel")
-	case TSX:
-		return formatJavaScript(code, "typescript")
-	case Python:
-		return formatPython(code)
-	default:
-		return formatGo(code)
-	}
+var internalFormatters = map[string]func(string) (string, error){
+	"gofmt": formatGo,
 }
 
-func formatGo(code string) (string, error) {
-	formattedBytes, err := format.Source([]byte(code))
-	if err != nil {
-		return code, fmt.Errorf("go formatting error: %v", err)
+func FormatCode(code string, lang Language) (string, error) {
+	registered, ok := languages.Lookup(string(lang))
+	if !ok || len(registered.Formatter) == 0 {
+		return code, fmt.Errorf("%s formatter not found", languages.Canonical(string(lang)))
 	}
-	return string(formattedBytes), nil
-}
-func formatCPP(code string) (string, error) {
-	return formatWithExternalTool(code, "clang-format", []string{}, "C/C++",
-		"Install clang-format: https://clang.llvm.org/docs/ClangFormat.html")
-}
 
-func formatJava(code string) (string, error) {
-	if _, err := exec.LookPath("google-java-format"); err == nil {
-		return formatWithExternalTool(code, "google-java-format", []string{"-"}, "Java",
-			"Install google-java-format: https://github.com/google/google-java-format")
+	help := registered.FormatterHelp
+	if help == "" {
+		help = fmt.Sprintf("Install %s or change the formatter in %s", registered.Formatter[0], languages.DefaultConfigFile())
 	}
 
-	if _, err := exec.LookPath("java"); err == nil {
-		return formatWithExternalTool(code, "java", []string{"-jar", "/usr/local/lib/google-java-format.jar", "-"}, "Java",
-			"Install google-java-format: https://github.com/google/google-java-format")
+	for _, command := range append([][]string{registered.Formatter}, registered.FormatterFallbacks...) {
+		if format, ok := internalFormatters[command[0]]; ok {
+			return format(code)
+		}
+		if _, err := exec.LookPath(command[0]); err == nil {
+			return formatWithExternalTool(code, command[0], command[1:], registered.Title, help)
+		}
 	}
 
-	return code, fmt.Errorf("java formatter not found")
+	return code, fmt.Errorf("%s formatter not found. %s", registered.Title, help)
 }
 
-func formatJavaScript(code string, parser string) (string, error) {
-	return formatWithExternalTool(code, "prettier", []string{"--stdin", "--parser", parser},
-		fmt.Sprintf("JavaScript (%s)", parser),
-		"Install prettier: npm install -g prettier")
-}
-
-func formatPython(code string) (string, error) {
-	if _, err := exec.LookPath("black"); err == nil {
-		return formatWithExternalTool(code, "black", []string{"-", "-q"}, "Python",
-			"Install black: pip install black")
-	}
-
-	if _, err := exec.LookPath("python"); err == nil {
-		return formatWithExternalTool(code, "python", []string{"-m", "black", "-", "-q"}, "Python",
-			"Install black: pip install black")
-	}
-
-	if _, err := exec.LookPath("python3"); err == nil {
-		return formatWithExternalTool(code, "python3", []string{"-m", "black", "-", "-q"}, "Python",
-			"Install black: pip install black")
+func formatGo(code string) (string, error) {
+	formattedBytes, err := format.Source([]byte(code))
+	if err != nil {
+		return code, fmt.Errorf("go formatting error: %v", err)
 	}
-
-	return code, fmt.Errorf("python formatter not found")
+	return string(formattedBytes), nil
 }
 
 func formatWithExternalTool(code, command string, args []string, language, installInstructions string) (string, error) {
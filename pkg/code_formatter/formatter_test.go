@@ -0,0 +1,30 @@
+package codeformatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+func TestFormatCode(t *testing.T) {
+	got, err := FormatCode("package main\nfunc  main( ) {}\n", Go)
+	if err != nil || got != "package main\n\nfunc main() {}\n" {
+		t.Errorf("FormatCode(go) = %q, %v", got, err)
+	}
+
+	if _, err := FormatCode("x", "dockerfile"); err == nil || !strings.Contains(err.Error(), "formatter not found") {
+		t.Errorf("FormatCode(dockerfile) error = %v, want formatter not found", err)
+	}
+
+	if err := languages.Register(languages.Language{
+		Name:               "fmttest",
+		Formatter:          []string{"definitely-not-installed-formatter"},
+		FormatterFallbacks: [][]string{{"cat"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := FormatCode("keep me\n", "fmttest"); err != nil || got != "keep me\n" {
+		t.Errorf("FormatCode(fmttest) = %q, %v; want the fallback to run", got, err)
+	}
+}
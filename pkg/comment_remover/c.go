@@ -0,0 +1,187 @@
+package commentremover
+
+import (
+	"strings"
+)
+
+func scanCPP(l *lexer) bool {
+	switch c := l.src[l.pos]; {
+	case c == '#' && l.onlySpaceBefore(l.pos):
+		return scanPreprocessor(l)
+	case c == '\'':
+		if cppDigitSeparator(l.src, l.pos) {
+			l.pos++
+			return true
+		}
+		l.emit(stringToken, l.stringEnd(singleQuoted))
+	case c == 'R' || c == 'u' || c == 'U' || c == 'L':
+		return scanCPPRawString(l)
+	default:
+		return false
+	}
+	return true
+}
+
+func cppDigitSeparator(src string, pos int) bool {
+	j := pos - 1
+	for j >= 0 && (isIdentByte(src[j]) || src[j] == '\'' || src[j] == '.') {
+		j--
+	}
+	return j+1 < pos && src[j+1] >= '0' && src[j+1] <= '9'
+}
+
+func scanCPPRawString(l *lexer) bool {
+	if l.pos > 0 && isIdentByte(l.src[l.pos-1]) {
+		return false
+	}
+
+	i := l.pos
+	for _, prefix := range []string{"u8", "u", "U", "L"} {
+		if strings.HasPrefix(l.src[i:], prefix+"R\"") {
+			i += len(prefix)
+			break
+		}
+	}
+	if !strings.HasPrefix(l.src[i:], "R\"") {
+		return false
+	}
+
+	open := strings.IndexByte(l.src[i+2:], '(')
+	if open == -1 || open > 16 || strings.ContainsAny(l.src[i+2:i+2+open], " \\)\t\n") {
+		return false
+	}
+
+	closing := ")" + l.src[i+2:i+2+open] + "\""
+	l.emit(stringToken, indexEnd(l.src, i+2+open+1, closing))
+	return true
+}
+
+func scanPreprocessor(l *lexer) bool {
+	i := l.pos + 1
+	for i < len(l.src) && (l.src[i] == ' ' || l.src[i] == '\t') {
+		i++
+	}
+	j := i
+	for j < len(l.src) && isLetter(l.src[j]) {
+		j++
+	}
+
+	switch l.src[i:j] {
+	case "include", "include_next", "import":
+		k := j
+		for k < len(l.src) && (l.src[k] == ' ' || l.src[k] == '\t') {
+			k++
+		}
+		l.pos = k
+		if k < len(l.src) && l.src[k] == '<' {
+			if end := strings.IndexAny(l.src[k:], ">\n"); end != -1 && l.src[k+end] == '>' {
+				l.emit(stringToken, k+end+1)
+			}
+		}
+	case "error", "warning":
+		l.pos = j
+		for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+			if !l.lexLineComment() && !l.lexBlockComment() {
+				l.pos++
+			}
+		}
+	default:
+		l.pos = j
+	}
+	return true
+}
+
+func cDeadBlocks(code string, tokens []token) []token {
+	var blocks []token
+
+	t := 0
+	for lineStart := 0; lineStart < len(code); {
+		lineEnd := len(code)
+		if idx := strings.IndexByte(code[lineStart:], '\n'); idx != -1 {
+			lineEnd = lineStart + idx
+		}
+
+		for t < len(tokens) && tokens[t].end <= lineStart {
+			t++
+		}
+		inToken := t < len(tokens) && tokens[t].start < lineStart
+
+		if !inToken && preprocessorDirective(code[lineStart:lineEnd]) == "if 0" {
+			if dead, next := cDeadBlock(code, lineStart); dead != nil {
+				blocks = append(blocks, dead...)
+				lineStart = next
+				continue
+			}
+		}
+
+		lineStart = lineEnd + 1
+	}
+
+	return blocks
+}
+
+func cDeadBlock(code string, start int) ([]token, int) {
+	depth := 0
+	elseLine := -1
+
+	for lineStart := start; lineStart < len(code); {
+		lineEnd := len(code)
+		if idx := strings.IndexByte(code[lineStart:], '\n'); idx != -1 {
+			lineEnd = lineStart + idx
+		}
+
+		directive := preprocessorDirective(code[lineStart:lineEnd])
+		name, _, _ := strings.Cut(directive, " ")
+		switch name {
+		case "if", "ifdef", "ifndef":
+			depth++
+		case "elif", "elifdef", "elifndef":
+			if depth == 1 {
+				return nil, 0
+			}
+		case "else":
+			if depth == 1 {
+				elseLine = lineStart
+			}
+		case "endif":
+			depth--
+			if depth == 0 {
+				next := min(lineEnd+1, len(code))
+				if elseLine == -1 {
+					return []token{{kind: deadCodeToken, start: start, end: lineEnd}}, next
+				}
+				return []token{
+					{kind: deadCodeToken, start: start, end: lineEndAt(code, elseLine)},
+					{kind: deadCodeToken, start: lineStart, end: lineEnd},
+				}, next
+			}
+		}
+
+		lineStart = lineEnd + 1
+	}
+
+	return nil, 0
+}
+
+func preprocessorDirective(line string) string {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return ""
+	}
+
+	line = strings.TrimSpace(line[1:])
+	if idx := strings.Index(line, "//"); idx != -1 {
+		line = line[:idx]
+	}
+	if idx := strings.Index(line, "/*"); idx != -1 {
+		line = line[:idx]
+	}
+	return strings.Join(strings.Fields(line), " ")
+}
+
+func lineEndAt(code string, pos int) int {
+	if idx := strings.IndexByte(code[pos:], '\n'); idx != -1 {
+		return pos + idx
+	}
+	return len(code)
+}
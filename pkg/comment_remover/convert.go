@@ -0,0 +1,375 @@
+package commentremover
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+var (
+	docstringQuotePattern = regexp.MustCompile(`^[rRuUbBfF]*("""|'''|"|')`)
+	pythonHeaderPattern   = regexp.MustCompile(`^\s*(async\s+def|def|class)\s`)
+)
+
+type edit struct {
+	start int
+	end   int
+	text  string
+}
+
+func Convert(code string, opts Options, target string) (string, error) {
+	to, ok := languages.Lookup(target)
+	if !ok {
+		return code, fmt.Errorf("unknown target language %q", target)
+	}
+	if len(to.LineComments) == 0 && len(to.BlockComments) == 0 {
+		return code, fmt.Errorf("%s has no comment syntax", to.Title)
+	}
+	from, _ := languages.Lookup(opts.Language)
+
+	result, err := Remove(code, opts)
+
+	kinds := map[int]tokenKind{}
+	for _, t := range tokenize(code, languages.Canonical(opts.Language)) {
+		kinds[t.start] = t.kind
+	}
+
+	var edits []edit
+	for i, span := range result.Removed {
+		if span.Kind == BareString || span.Kind == DeadCode {
+			continue
+		}
+
+		body := commentBody(span, kinds[span.Start.Offset], from)
+		doc := span.Kind == DocComment || span.Kind == Docstring
+		lineStart := strings.LastIndexByte(code[:span.Start.Offset], '\n') + 1
+		indent := code[lineStart:span.Start.Offset]
+		before := strings.TrimSpace(indent) != ""
+		after := codeFollows(code, result.Removed, i)
+
+		if span.Kind == JSXComment {
+			if len(to.BlockComments) > 0 {
+				block := to.BlockComments[0]
+				edits = append(edits, edit{span.Start.Offset, span.End.Offset, "{" + renderBlock([]string{strings.Join(body, " ")}, block.Open, block.Close, "") + "}"})
+			}
+			continue
+		}
+
+		if after && len(to.BlockComments) == 0 {
+			lineIndent := leadingSpace(code[lineStart:])
+			end := span.End.Offset + len(leadingSpace(code[span.End.Offset:]))
+			edits = append(edits,
+				edit{lineStart, lineStart, lineIndent + renderComment(body, to, doc, lineIndent) + "\n"},
+				edit{span.Start.Offset, end, ""},
+			)
+			continue
+		}
+
+		if before || after {
+			line := []string{strings.Join(body, " ")}
+			text := renderComment(line, to, doc, "")
+			if after {
+				text = renderInlineBlock(line, to, doc)
+			}
+			edits = append(edits, edit{span.Start.Offset, span.End.Offset, text})
+			continue
+		}
+
+		if span.Kind == Docstring {
+			if header, ok := docstringHeader(code, lineStart); ok {
+				headerIndent := leadingSpace(code[header:])
+				lineEnd := span.End.Offset + len(code[span.End.Offset:]) - len(strings.TrimLeft(code[span.End.Offset:], " \t\r"))
+				if lineEnd < len(code) && code[lineEnd] == '\n' {
+					lineEnd++
+				}
+				edits = append(edits,
+					edit{header, header, headerIndent + renderComment(body, to, doc, headerIndent) + "\n"},
+					edit{lineStart, lineEnd, ""},
+				)
+				continue
+			}
+		}
+
+		end := span.Start.Offset + len(strings.TrimRight(span.Text, " \t\r\n"))
+		edits = append(edits, edit{span.Start.Offset, end, renderComment(body, to, doc, indent)})
+	}
+
+	slices.SortStableFunc(edits, func(a, b edit) int {
+		return a.start - b.start
+	})
+
+	var out strings.Builder
+	prev := 0
+	for _, e := range edits {
+		out.WriteString(code[prev:e.start])
+		out.WriteString(e.text)
+		prev = e.end
+	}
+	out.WriteString(code[prev:])
+	return out.String(), err
+}
+
+func commentBody(span Span, kind tokenKind, from languages.Language) []string {
+	text := span.Text
+
+	switch span.Kind {
+	case Docstring:
+		if m := docstringQuotePattern.FindStringSubmatch(text); m != nil {
+			text = strings.TrimSuffix(text[len(m[0]):], m[1])
+		}
+		return trimBody(strings.Split(text, "\n"), false)
+	case JSXComment:
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "{"), "}"))
+		kind = blockCommentToken
+		if longestPrefix(text, from.LineComments) != "" {
+			kind = lineCommentToken
+		}
+	}
+
+	if kind == lineCommentToken {
+		return lineCommentBody(text, append(slices.Clone(from.DocComments), from.LineComments...))
+	}
+
+	switch from.Lexer {
+	case "lua":
+		if level, ok := longBracketLevel(text, 2); ok && strings.HasPrefix(text, "--") {
+			inner := strings.TrimSuffix(text[level+4:], "]"+strings.Repeat("=", level)+"]")
+			return trimBody(strings.Split(inner, "\n"), false)
+		}
+	case "perl", "ruby":
+		if strings.HasPrefix(text, "=") {
+			return podBody(text)
+		}
+	}
+
+	if open, close, ok := blockDelimiters(text, from); ok {
+		inner := strings.TrimSuffix(text[len(open):], close)
+		return trimBody(strings.Split(inner, "\n"), strings.Contains(open, "*"))
+	}
+	return trimBody(strings.Split(text, "\n"), false)
+}
+
+func lineCommentBody(text string, prefixes []string) []string {
+	lines := strings.Split(strings.TrimRight(text, " \t\r\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if i < len(lines)-1 {
+			line = strings.TrimRight(strings.TrimSuffix(line, "\\"), " \t")
+		}
+		if i > 0 {
+			line = strings.TrimLeft(line, " \t")
+		}
+		if prefix := longestPrefix(line, prefixes); prefix != "" {
+			line = line[len(prefix):]
+			if !strings.HasSuffix(prefix, " ") {
+				line = strings.TrimPrefix(line, " ")
+			}
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+func podBody(text string) []string {
+	lines := strings.Split(strings.TrimRight(text, " \t\r\n"), "\n")
+	if command, _, _ := strings.Cut(strings.TrimSpace(lines[len(lines)-1]), " "); len(lines) > 1 && (command == "=cut" || command == "=end") {
+		lines = lines[:len(lines)-1]
+	}
+	_, lines[0], _ = strings.Cut(lines[0], " ")
+	return trimBody(lines, false)
+}
+
+func codeFollows(code string, spans []Span, i int) bool {
+	pos := spans[i].End.Offset
+	for next := i + 1; ; next++ {
+		pos += len(leadingSpace(code[pos:]))
+		if pos == len(code) || code[pos] == '\n' || code[pos] == '\r' {
+			return false
+		}
+		if next == len(spans) || spans[next].Start.Offset != pos {
+			return true
+		}
+		pos = spans[next].End.Offset
+	}
+}
+
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
+
+func blockDelimiters(text string, lang languages.Language) (string, string, bool) {
+	open, close := "", ""
+	for _, block := range lang.BlockComments {
+		if strings.HasPrefix(text, block.Open) && strings.HasSuffix(text, block.Close) && len(block.Open) > len(open) {
+			open, close = block.Open, block.Close
+		}
+	}
+	if open == "" {
+		return "", "", false
+	}
+	for _, prefix := range lang.DocComments {
+		if strings.HasPrefix(prefix, open) && strings.HasPrefix(text, prefix) && len(prefix) > len(open) {
+			open = prefix
+		}
+	}
+	return open, close, len(text) >= len(open)+len(close)
+}
+
+func longestPrefix(text string, prefixes []string) string {
+	longest := ""
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	return longest
+}
+
+func trimBody(lines []string, stars bool) []string {
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if stars && i > 0 {
+			if trimmed := strings.TrimLeft(line, " \t"); strings.HasPrefix(trimmed, "*") {
+				line = strings.TrimPrefix(trimmed[1:], " ")
+			}
+		}
+		lines[i] = line
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+
+	for len(lines) > 1 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	common := -1
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); common == -1 || n < common {
+			common = n
+		}
+	}
+	for i := 1; i < len(lines) && common > 0; i++ {
+		if lines[i] != "" {
+			lines[i] = lines[i][common:]
+		}
+	}
+	return lines
+}
+
+func docPrefixes(to languages.Language) (lineDoc, blockDoc string) {
+	for _, prefix := range to.DocComments {
+		if !slices.ContainsFunc(to.BlockComments, func(b languages.Delimiter) bool { return strings.HasPrefix(prefix, b.Open) }) {
+			if lineDoc == "" {
+				lineDoc = prefix
+			}
+		} else if blockDoc == "" {
+			blockDoc = prefix
+		}
+	}
+	return lineDoc, blockDoc
+}
+
+func renderComment(body []string, to languages.Language, doc bool, indent string) string {
+	lineDoc, blockDoc := docPrefixes(to)
+
+	switch {
+	case doc && lineDoc != "":
+		return renderLines(body, lineDoc, docContinuation(lineDoc, to.LineComments), indent)
+	case doc && blockDoc != "":
+		return renderBlock(body, blockDoc, blockClose(blockDoc, to), indent)
+	case len(to.LineComments) > 0:
+		return renderLines(body, to.LineComments[0], to.LineComments[0], indent)
+	default:
+		return renderBlock(body, to.BlockComments[0].Open, to.BlockComments[0].Close, indent)
+	}
+}
+
+func renderInlineBlock(body []string, to languages.Language, doc bool) string {
+	if _, blockDoc := docPrefixes(to); doc && blockDoc != "" {
+		return renderBlock(body, blockDoc, blockClose(blockDoc, to), "")
+	}
+	return renderBlock(body, to.BlockComments[0].Open, to.BlockComments[0].Close, "")
+}
+
+func docContinuation(prefix string, lineComments []string) string {
+	line := longestPrefix(prefix, lineComments)
+	if line == "" || len(prefix) == len(line) || prefix[len(line)] == line[len(line)-1] {
+		return prefix
+	}
+	return line
+}
+
+func blockClose(open string, to languages.Language) string {
+	for _, block := range to.BlockComments {
+		if strings.HasPrefix(open, block.Open) {
+			return block.Close
+		}
+	}
+	return to.BlockComments[0].Close
+}
+
+func renderLines(body []string, first, rest string, indent string) string {
+	lines := make([]string, len(body))
+	for i, line := range body {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line != "" && !strings.HasSuffix(prefix, " ") {
+			prefix += " "
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n"+indent)
+}
+
+func renderBlock(body []string, open, close string, indent string) string {
+	if len(body) == 1 {
+		return open + " " + body[0] + " " + close
+	}
+
+	decoration := "  "
+	closing := close
+	if strings.HasPrefix(open, "/*") {
+		decoration, closing = " * ", " "+close
+	}
+
+	lines := []string{open}
+	for _, line := range body {
+		lines = append(lines, strings.TrimRight(decoration+line, " "))
+	}
+	lines = append(lines, closing)
+	return strings.Join(lines, "\n"+indent)
+}
+
+func docstringHeader(code string, lineStart int) (int, bool) {
+	lines := strings.Split(code[:lineStart], "\n")
+	lines = lines[:len(lines)-1]
+
+	end := len(lines) - 1
+	for end >= 0 && strings.TrimSpace(lines[end]) == "" {
+		end--
+	}
+	if end < 0 || !strings.HasSuffix(strings.TrimSpace(lines[end]), ":") {
+		return 0, false
+	}
+
+	for i := end; i >= 0 && i > end-10; i-- {
+		if pythonHeaderPattern.MatchString(lines[i]) {
+			offset := 0
+			for _, line := range lines[:i] {
+				offset += len(line) + 1
+			}
+			return offset, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package commentremover
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		input string
+		want  string
+	}{
+		{
+			name:  "line, trailing and block comments",
+			from:  "go",
+			to:    "python",
+			input: "// Foo does.\nfunc Foo() {} // trailing\n/*\n * multi\n * line\n */\n",
+			want:  "# Foo does.\nfunc Foo() {} # trailing\n# multi\n# line\n",
+		},
+		{
+			name:  "docstring moves above its definition",
+			from:  "python",
+			to:    "go",
+			input: "def f():\n    \"\"\"Doc.\"\"\"\n    return 1  # one\n",
+			want:  "// Doc.\ndef f():\n    return 1  // one\n",
+		},
+		{
+			name:  "doc comments",
+			from:  "rust",
+			to:    "java",
+			input: "/// doc\nfn f() {}\n",
+			want:  "/** doc */\nfn f() {}\n",
+		},
+		{
+			name:  "block comment followed by code goes on the line above",
+			from:  "go",
+			to:    "python",
+			input: "x := 1 /* a\n b */ + 2\n",
+			want:  "# a\n# b\nx := 1 + 2\n",
+		},
+		{
+			name:  "indented block comment before code",
+			from:  "go",
+			to:    "python",
+			input: "\t/* a */ y := 2 // c\n",
+			want:  "\t# a\n\ty := 2 # c\n",
+		},
+		{
+			name:  "inline block comment in a block target",
+			from:  "go",
+			to:    "rust",
+			input: "x := 1 /* a\n b */ + 2\n",
+			want:  "x := 1 /* a b */ + 2\n",
+		},
+		{
+			name:  "jsx container keeps its braces",
+			from:  "jsx",
+			to:    "css",
+			input: "const a = <div>{// a\n}</div>;\n",
+			want:  "const a = <div>{/* a */}</div>;\n",
+		},
+		{
+			name:  "jsx container is never a bare line comment",
+			from:  "jsx",
+			to:    "python",
+			input: "const a = <div>{/* a */}</div>;\n",
+			want:  "const a = <div>{/* a */}</div>;\n",
+		},
+		{
+			name:  "perl pod",
+			from:  "perl",
+			to:    "go",
+			input: "=pod\n\nDocs here\n\n=cut\nmy $x = 1;\n",
+			want:  "// Docs here\nmy $x = 1;\n",
+		},
+		{
+			name:  "unterminated perl pod",
+			from:  "perl",
+			to:    "go",
+			input: "=head1 NAME\n\nFoo\n",
+			want:  "// NAME\n//\n// Foo\n",
+		},
+		{
+			name:  "ruby block comment",
+			from:  "ruby",
+			to:    "go",
+			input: "=begin\nhello\n=end\nx = 1\n",
+			want:  "// hello\nx = 1\n",
+		},
+		{
+			name:  "lua long bracket comment",
+			from:  "lua",
+			to:    "go",
+			input: "--[==[ a\nb ]==]\nx = 1\n",
+			want:  "// a\n// b\nx = 1\n",
+		},
+		{
+			name:  "c line continuation",
+			from:  "c",
+			to:    "python",
+			input: "// a \\\n b\nint x;\n",
+			want:  "# a\n# b\nint x;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.input, DefaultOptions(tt.from), tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%q, %q -> %q)\n got %q\nwant %q", tt.input, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package commentremover
+
+import "strings"
+
+func scanCSSURL(l *lexer) bool {
+	if l.pos+4 > len(l.src) || !strings.EqualFold(l.src[l.pos:l.pos+4], "url(") {
+		return false
+	}
+	if l.pos > 0 && (isIdentByte(l.src[l.pos-1]) || l.src[l.pos-1] == '-') {
+		return false
+	}
+
+	i := l.pos + 4
+	for i < len(l.src) && strings.IndexByte(" \t\r\n", l.src[i]) >= 0 {
+		i++
+	}
+	if i < len(l.src) && (l.src[i] == '"' || l.src[i] == '\'') {
+		l.pos = i
+		return true
+	}
+
+	end := strings.IndexByte(l.src[i:], ')')
+	if end == -1 || strings.IndexByte(l.src[i:i+end], '\n') != -1 {
+		l.pos = i
+		return true
+	}
+
+	l.pos = i
+	l.emit(stringToken, i+end)
+	return true
+}
@@ -0,0 +1,131 @@
+package commentremover
+
+import (
+	"strings"
+)
+
+func scanMySQLComment(l *lexer) bool {
+	if !l.hasPrefix("--") {
+		return false
+	}
+
+	if next := l.pos + 2; next < len(l.src) && strings.IndexByte(" \t\r\n", l.src[next]) == -1 {
+		l.pos += 2
+		return true
+	}
+
+	l.emit(lineCommentToken, l.lineEnd(l.pos))
+	return true
+}
+
+func scanPostgresString(l *lexer) bool {
+	switch c := l.src[l.pos]; {
+	case (c == 'E' || c == 'e') && l.pos+1 < len(l.src) && l.src[l.pos+1] == '\'':
+		if l.pos > 0 && isIdentByte(l.src[l.pos-1]) {
+			return false
+		}
+		end := quotedEnd(l.src, l.pos+2, '\'')
+		l.emit(stringToken, end)
+	case c == '$':
+		tag, ok := dollarQuoteTag(l.src, l.pos)
+		if !ok {
+			return false
+		}
+		from := l.pos + len(tag)
+		idx := strings.Index(l.src[from:], tag)
+		if idx == -1 {
+			l.emit(stringToken, len(l.src))
+		} else {
+			l.emit(stringToken, from+idx+len(tag))
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func dollarQuoteTag(src string, pos int) (string, bool) {
+	if pos > 0 && isIdentByte(src[pos-1]) {
+		return "", false
+	}
+
+	i := pos + 1
+	for i < len(src) && isIdentByte(src[i]) {
+		i++
+	}
+	if i >= len(src) || src[i] != '$' || i > pos+1 && src[pos+1] >= '0' && src[pos+1] <= '9' {
+		return "", false
+	}
+	return src[pos : i+1], true
+}
+
+func scanLua(l *lexer) bool {
+	switch {
+	case l.hasPrefix("--"):
+		if level, ok := longBracketLevel(l.src, l.pos+2); ok {
+			l.emit(blockCommentToken, longBracketEnd(l.src, l.pos+2, level))
+		} else {
+			l.emit(lineCommentToken, l.lineEnd(l.pos))
+		}
+	case l.src[l.pos] == '[':
+		level, ok := longBracketLevel(l.src, l.pos)
+		if !ok {
+			return false
+		}
+		l.emit(stringToken, longBracketEnd(l.src, l.pos, level))
+	default:
+		return false
+	}
+	return true
+}
+
+func longBracketLevel(src string, pos int) (int, bool) {
+	if pos >= len(src) || src[pos] != '[' {
+		return 0, false
+	}
+
+	level := 0
+	i := pos + 1
+	for i < len(src) && src[i] == '=' {
+		level++
+		i++
+	}
+	return level, i < len(src) && src[i] == '['
+}
+
+func longBracketEnd(src string, pos int, level int) int {
+	from := pos + level + 2
+	closing := "]" + strings.Repeat("=", level) + "]"
+	idx := strings.Index(src[from:], closing)
+	if idx == -1 {
+		return len(src)
+	}
+	return from + idx + len(closing)
+}
+
+const haskellSymbols = "!#$%&*+./<=>?@\\^|~:"
+
+func scanHaskell(l *lexer) bool {
+	switch c := l.src[l.pos]; {
+	case l.hasPrefix("--"):
+		i := l.pos
+		for i < len(l.src) && l.src[i] == '-' {
+			i++
+		}
+		if i < len(l.src) && strings.IndexByte(haskellSymbols, l.src[i]) >= 0 ||
+			l.pos > 0 && strings.IndexByte(haskellSymbols, l.src[l.pos-1]) >= 0 {
+			l.pos = i
+			return true
+		}
+		l.emit(lineCommentToken, l.lineEnd(l.pos))
+	case c == '\'':
+		if l.pos > 0 && (isIdentByte(l.src[l.pos-1]) || l.src[l.pos-1] == '\'') {
+			l.pos++
+			return true
+		}
+		return scanRustChar(l)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,392 @@
+package commentremover
+
+import (
+	"cmp"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+type Detection struct {
+	Language   string
+	Confidence float64
+}
+
+type signal struct {
+	pattern *regexp.Regexp
+	weight  float64
+}
+
+func signals(weighted map[string]float64) []signal {
+	var list []signal
+	for pattern, weight := range weighted {
+		list = append(list, signal{regexp.MustCompile(pattern), weight})
+	}
+	return list
+}
+
+var languageSignals = map[string][]signal{
+	"go": signals(map[string]float64{
+		`(?m)^package \w+\s*$`:              4,
+		`(?m)^func (\(\w+ \*?\w+\) )?\w+\(`: 3,
+		`\w+ := `:                           2,
+		`\berr != nil\b`:                    4,
+		`(?m)^import \($`:                   3,
+		`\bfmt\.\w+\(`:                      2,
+		`\bchan\b|\bgo func\b|\bdefer\b`:    2,
+	}),
+	"python": signals(map[string]float64{
+		`(?m)^\s*def \w+\(.*\)( -> [^:]+)?:\s*$`: 4,
+		`(?m)^\s*class \w+(\(.*\))?:\s*$`:        3,
+		`(?m)^from [\w.]+ import \w+`:            3,
+		`(?m)^import \w+(\.\w+)*( as \w+)?\s*$`:  1,
+		`(?m)^\s*(elif|except|finally)\b.*:\s*$`: 3,
+		`\bself\.\w+`:                            2,
+		`\b(None|True|False)\b`:                  1,
+		`(?m)^\s*@\w+(\.\w+)*(\(.*\))?\s*$`:      1,
+	}),
+	"java": signals(map[string]float64{
+		`\b(public|private|protected) (static )?(final )?(class|interface|enum|void|\w+(<.*>)?) \w+`: 3,
+		`\bSystem\.out\.print`: 4,
+		`(?m)^import java\.`:   4,
+		`@Override\b`:          3,
+		`\bnew \w+(<.*>)?\(`:   1,
+	}),
+	"c": signals(map[string]float64{
+		`(?m)^#\s*(include|define|ifdef|ifndef|pragma)\b`: 4,
+		`\bint main\s*\(`: 3,
+		`\bprintf\s*\(`:   2,
+		`\bstd::\w+`:      3,
+		`\b(nullptr|size_t|struct|typedef|unsigned)\b`: 2,
+		`\w+->\w+`:                             1,
+		`\b(char|int|void|double)\s*\*+\s*\w+`: 2,
+	}),
+	"rust": signals(map[string]float64{
+		`(?m)^\s*(pub )?fn \w+(<.*>)?\(`: 3,
+		`\blet mut\b`:                    4,
+		`(?m)^\s*(impl|trait|mod)\b`:     3,
+		`(?m)^use \w+(::\w+)+`:           3,
+		`\b\w+!\(`:                       1,
+		`&mut\b|&self\b|\bSome\(|\bOk\(`: 3,
+	}),
+	"swift": signals(map[string]float64{
+		`(?m)^import (Foundation|UIKit|SwiftUI)\b`: 5,
+		`\bguard let\b|\bif let\b`:                 3,
+		`\bfunc \w+\(.*\) -> `:                     2,
+		`\bvar \w+: \w+`:                           1,
+		`@(State|Published|objc)\b`:                3,
+	}),
+	"kotlin": signals(map[string]float64{
+		`(?m)^\s*(private |internal |override |suspend )*fun \w+\(`: 4,
+		`\bval \w+( *: *\w+)? =`:                                    2,
+		`\bdata class\b|\bcompanion object\b`:                       4,
+		`(?m)^package [\w.]+\s*$`:                                   1,
+		`\bprintln\(`:                                               1,
+	}),
+	"scala": signals(map[string]float64{
+		`(?m)^\s*(case )?(object|class|trait) \w+`: 2,
+		`\bdef \w+(\[.*\])?\(.*\)\s*:\s*\w+`:       3,
+		`\bcase class\b`:                           4,
+		`\bimplicit\b|\bsealed trait\b`:            3,
+	}),
+	"bash": signals(map[string]float64{
+		`(?m)^\s*(fi|esac|done|then)\s*$`:     3,
+		`(?m)^\s*if \[\[? `:                   3,
+		`\$\{?\w+\}?`:                         1,
+		`(?m)^\s*(echo|export|local|source) `: 2,
+		`\$\(\w+`:                             1,
+	}),
+	"ruby": signals(map[string]float64{
+		`(?m)^\s*def \w+[?!]?(\(.*\))?\s*$`:   3,
+		`(?m)^\s*end\s*$`:                     2,
+		`\bputs\b|\battr_(reader|accessor)\b`: 3,
+		`(?m)^require ['"]`:                   3,
+		`\bdo \|\w+(, \w+)*\|`:                3,
+	}),
+	"perl": signals(map[string]float64{
+		`\bmy [$@%]\w+`:               4,
+		`(?m)^use (strict|warnings);`: 5,
+		`(?m)^\s*sub \w+ \{`:          3,
+		`\$_\b|@ARGV\b`:               2,
+	}),
+	"yaml": signals(map[string]float64{
+		`(?m)^[\w-]+:( .*)?$`:     2,
+		`(?m)^\s+- [\w"'{[]`:      1,
+		`(?m)^---\s*$`:            2,
+		`(?m)^\s+[\w-]+: [^{;]*$`: 1,
+	}),
+	"toml": signals(map[string]float64{
+		`(?m)^\[\[?[\w.-]+\]\]?\s*$`: 4,
+		`(?m)^[\w.-]+ = ["\d\[{tf]`:  2,
+	}),
+	"dockerfile": signals(map[string]float64{
+		`(?m)^FROM \S+`: 5,
+		`(?m)^(RUN|COPY|ADD|CMD|ENTRYPOINT|WORKDIR|ENV|EXPOSE|ARG|LABEL) `: 3,
+	}),
+	"makefile": signals(map[string]float64{
+		`(?m)^[\w./-]+( [\w./-]+)*:( [^=].*)?$`: 1,
+		`(?m)^\t\S`:                             2,
+		`\$\(\w+\)|\$@|\$<|\$\^`:                3,
+		`(?m)^\.PHONY:`:                         5,
+	}),
+	"sql": signals(map[string]float64{
+		`(?i)\bselect\b[\s\S]+?\bfrom\b`:                   4,
+		`(?i)\b(insert into|update \w+ set|delete from)\b`: 4,
+		`(?i)\bcreate (table|index|view|function)\b`:       4,
+		`(?i)\b(where|group by|order by|join|values)\b`:    1,
+	}),
+	"lua": signals(map[string]float64{
+		`\blocal \w+ =`:                       3,
+		`(?m)^\s*(local )?function [\w.:]+\(`: 3,
+		`\bthen\s*$|\bthen\b`:                 1,
+		`~=|\.\.`:                             1,
+		`(?m)^\s*end\s*$`:                     1,
+	}),
+	"haskell": signals(map[string]float64{
+		`(?m)^module [\w.]+`:      4,
+		`(?m)^import qualified\b`: 4,
+		`(?m)^\w+ :: .+`:          4,
+		`\bwhere\s*$`:             1,
+		`<-|\bdo\s*$`:             1,
+	}),
+	"html": signals(map[string]float64{
+		`(?i)<!doctype html`: 5,
+		`(?i)<(html|head|body|div|span|p|ul|li|a|section)\b[^>]*>`: 2,
+		`(?i)</(div|span|p|body|html)>`:                            2,
+	}),
+	"css": signals(map[string]float64{
+		`(?m)^\s*[.#]?[\w-]+(\s*[,>+~]?\s*[.#:]?[\w-]+)*\s*\{\s*$`: 2,
+		`(?m)^\s*[\w-]+\s*:\s*[^;{}]+;\s*$`:                        2,
+		`@media\b|@import\b`:                                       2,
+	}),
+}
+
+var jsSignals = signals(map[string]float64{
+	`\b(const|let) \w+ =`:            2,
+	`=>`:                             1,
+	`\bfunction\s*\w*\(`:             2,
+	`\bconsole\.\w+\(`:               3,
+	`\brequire\(['"]`:                3,
+	`(?m)^(import .* from |export )`: 2,
+	`===|!==`:                        2,
+	`\bdocument\.\w+|\bwindow\.\w+`:  2,
+})
+
+var tsSignals = signals(map[string]float64{
+	`:\s*(string|number|boolean|any|unknown|void|never)\b`: 3,
+	`(?m)^\s*(export )?(interface|type) \w+`:               3,
+	`\bas const\b|\bas \w+\b`:                              1,
+	`\b(private|public|readonly) \w+:`:                     2,
+	`\w+<\w+(, \w+)*>\(`:                                   1,
+	`\(\w+\??: [A-Z]\w*(<.*>)?(\[\])?[,)]`:                 3,
+})
+
+var jsxSignals = signals(map[string]float64{
+	`return \(\s*<\w`:                        4,
+	`<\w+( [\w-]+=("[^"]*"|\{[^}]*\}))* ?/>`: 3,
+	`\bclassName=`:                           4,
+	`</[A-Z]\w*>`:                            2,
+})
+
+var sqlDialectSignals = map[string][]signal{
+	"mysql": signals(map[string]float64{
+		"`\\w+`":                 2,
+		`(?i)\bauto_increment\b`: 4,
+		`(?i)\bengine\s*=`:       4,
+		`(?m)^#`:                 1,
+	}),
+	"postgresql": signals(map[string]float64{
+		`::\w+`: 3,
+		`(?i)\b(serial|bigserial|jsonb|returning|ilike)\b`: 3,
+		`\$\w*\$`: 3,
+	}),
+}
+
+var markupSignals = map[string][]signal{
+	"vue": signals(map[string]float64{
+		`(?m)^<template>`:                      5,
+		`\bv-(if|for|bind|model|on)\b|@click=`: 3,
+	}),
+	"svelte": signals(map[string]float64{
+		`\{#(if|each|await)\b`: 5,
+		`(?m)^\s*\$: `:         3,
+		`\bon:\w+=`:            3,
+	}),
+	"xml": signals(map[string]float64{
+		`^\s*<\?xml\b`:  5,
+		`xmlns(:\w+)?=`: 2,
+	}),
+}
+
+var stylesheetSignals = map[string][]signal{
+	"scss": signals(map[string]float64{
+		`(?m)^\s*\$[\w-]+\s*:`:          4,
+		`@(mixin|include|extend|use)\b`: 4,
+		`&[:.\-]`:                       2,
+	}),
+	"less": signals(map[string]float64{
+		`(?m)^\s*@[\w-]+\s*:`: 4,
+		`\.[\w-]+\(\);`:       3,
+		`~"`:                  2,
+	}),
+}
+
+var shebangLanguages = map[string]string{
+	"python": "python", "python2": "python", "python3": "python",
+	"bash": "bash", "sh": "bash", "zsh": "bash", "dash": "bash", "ksh": "bash",
+	"node": "javascript", "deno": "typescript", "ts-node": "typescript", "bun": "javascript",
+	"ruby": "ruby", "perl": "perl", "lua": "lua", "runhaskell": "haskell", "runghc": "haskell",
+	"kotlin": "kotlin", "scala": "scala", "swift": "swift", "make": "makefile",
+}
+
+var shebangPattern = regexp.MustCompile(`^#!\s*(?:/usr)?(?:/local)?/bin/(?:env\s+(?:-\S+\s+)*)?(\S+)`)
+
+func DetectLanguage(code string) Detection {
+	if lang := fenceLanguage(code); lang != "" {
+		return Detection{Language: lang, Confidence: 1}
+	}
+	if m := shebangPattern.FindStringSubmatch(code); m != nil {
+		name := strings.TrimRight(m[1], "0123456789.")
+		if lang, ok := shebangLanguages[name]; ok {
+			return Detection{Language: lang, Confidence: 1}
+		}
+		if lang, ok := shebangLanguages[m[1]]; ok {
+			return Detection{Language: lang, Confidence: 1}
+		}
+	}
+
+	scores := scoreLanguages(code)
+
+	type candidate struct {
+		language string
+		score    float64
+	}
+	var candidates []candidate
+	for language, score := range scores {
+		if score > 0 {
+			candidates = append(candidates, candidate{language, score})
+		}
+	}
+	byScore := func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(a.language, b.language))
+	}
+	slices.SortFunc(candidates, byScore)
+
+	for i := range candidates[:min(len(candidates), 4)] {
+		var unterminated *UnterminatedError
+		if _, err := Remove(code, DefaultOptions(candidates[i].language)); errors.As(err, &unterminated) {
+			candidates[i].score *= 0.3
+		}
+	}
+	slices.SortFunc(candidates, byScore)
+
+	if len(candidates) == 0 {
+		return Detection{}
+	}
+
+	top, second := candidates[0].score, 0.0
+	if len(candidates) > 1 {
+		second = candidates[1].score
+	}
+	confidence := top / (top + second) * min(1, top/8)
+	return Detection{Language: candidates[0].language, Confidence: confidence}
+}
+
+func fenceLanguage(code string) string {
+	first, _, _ := strings.Cut(strings.TrimLeft(code, "\n"), "\n")
+	first = strings.TrimSpace(first)
+	for _, fence := range []string{"```", "~~~"} {
+		if info, ok := strings.CutPrefix(first, fence); ok {
+			name, _, _ := strings.Cut(strings.Trim(strings.TrimSpace(info), "{}."), " ")
+			if lang, ok := languages.Lookup(name); ok {
+				return lang.Name
+			}
+		}
+	}
+	return ""
+}
+
+func scoreLanguages(code string) map[string]float64 {
+	scores := map[string]float64{}
+	for language, list := range languageSignals {
+		scores[language] = score(code, list)
+	}
+
+	js, ts, jsx := score(code, jsSignals), score(code, tsSignals), score(code, jsxSignals)
+	switch {
+	case ts > 0 && jsx > 0:
+		scores["tsx"] = js + ts + jsx
+	case ts > 0:
+		scores["typescript"] = js + ts
+	case jsx > 0:
+		scores["jsx"] = js + jsx
+	default:
+		scores["javascript"] = js
+	}
+
+	specialise(code, scores, "sql", sqlDialectSignals)
+	specialise(code, scores, "html", markupSignals)
+	specialise(code, scores, "css", stylesheetSignals)
+
+	addPunctuationScores(code, scores)
+	return scores
+}
+
+func specialise(code string, scores map[string]float64, base string, dialects map[string][]signal) {
+	best, bestScore := "", 0.0
+	for dialect, list := range dialects {
+		if s := score(code, list); s > bestScore {
+			best, bestScore = dialect, s
+		}
+	}
+	if best == "" {
+		return
+	}
+	scores[best] = scores[base] + bestScore
+	delete(scores, base)
+}
+
+func score(code string, list []signal) float64 {
+	total := 0.0
+	for _, s := range list {
+		total += s.weight * float64(min(len(s.pattern.FindAllStringIndex(code, 3)), 3))
+	}
+	return total
+}
+
+func addPunctuationScores(code string, scores map[string]float64) {
+	var lines, semicolons, braces, colons int
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines++
+		switch line[len(line)-1] {
+		case ';':
+			semicolons++
+		case '{', '}':
+			braces++
+		case ':':
+			colons++
+		}
+	}
+	if lines == 0 {
+		return
+	}
+
+	ratio := func(n int) float64 { return float64(n) / float64(lines) }
+	for language := range scores {
+		switch language {
+		case "c", "java", "javascript", "typescript", "jsx", "tsx", "rust", "css", "scss", "less", "perl":
+			scores[language] += 4 * ratio(semicolons)
+		case "go", "kotlin", "swift", "scala":
+			scores[language] += 3 * ratio(braces) * (1 - ratio(semicolons))
+		case "python":
+			scores[language] += 6 * ratio(colons) * (1 - ratio(braces))
+		}
+	}
+}
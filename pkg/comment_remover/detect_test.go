@@ -0,0 +1,30 @@
+package commentremover
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "fence info string", input: "```rust\nlet x = 1;\n```\n", want: "rust"},
+		{name: "shebang", input: "#!/usr/bin/env python3\nprint('hi')\n", want: "python"},
+		{name: "go", input: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tif err := run(); err != nil {\n\t\tfmt.Println(err)\n\t}\n}\n", want: "go"},
+		{name: "python", input: "import os\n\ndef main():\n    for name in os.listdir('.'):\n        if name.endswith('.py'):\n            print(name)\n\nif __name__ == '__main__':\n    main()\n", want: "python"},
+		{name: "typescript", input: "interface User {\n  name: string;\n  age: number;\n}\n\nexport function greet(user: User): string {\n  const msg: string = `Hi ${user.name}`;\n  return msg;\n}\n", want: "typescript"},
+		{name: "sql", input: "CREATE TABLE users (\n  id INTEGER PRIMARY KEY,\n  name VARCHAR(100) NOT NULL\n);\n\nINSERT INTO users (id, name) VALUES (1, 'a');\n\nSELECT u.id, u.name\nFROM users u\nLEFT JOIN orders o ON o.user_id = u.id\nWHERE u.active = 1\nGROUP BY u.id\nORDER BY u.name;\n", want: "sql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.input); got.Language != tt.want || got.Confidence < 0.5 {
+				t.Errorf("DetectLanguage() = %s (%.2f), want %s", got.Language, got.Confidence, tt.want)
+			}
+		})
+	}
+
+	if got := DetectLanguage("x = 1"); got.Confidence >= 0.5 {
+		t.Errorf("DetectLanguage(%q) confidence = %.2f, want below 0.5", "x = 1", got.Confidence)
+	}
+}
@@ -0,0 +1,141 @@
+package commentremover
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ExtractOptions struct {
+	LineNumbers  bool
+	Declarations bool
+}
+
+type extractedLine struct {
+	number int
+	text   string
+}
+
+func Extract(code string, opts Options, extract ExtractOptions) (string, error) {
+	result, err := Remove(code, opts)
+
+	var spans []Span
+	for _, span := range result.Removed {
+		if span.Kind != DeadCode {
+			spans = append(spans, span)
+		}
+	}
+
+	var groups [][]extractedLine
+	for i := 0; i < len(spans); {
+		end := i + 1
+		if !isTrailingSpan(code, spans[i]) {
+			for end < len(spans) && continuesGroup(code, spans[end-1], spans[end]) {
+				end++
+			}
+		}
+
+		var group []extractedLine
+		for _, span := range spans[i:end] {
+			group = append(group, spanLines(span)...)
+		}
+		if extract.Declarations {
+			limit := len(code)
+			if end < len(spans) {
+				limit = spans[end].Start.Offset
+			}
+			if decl, ok := annotatedLine(code, spans[i], spans[end-1], limit); ok && decl.number < spans[i].Start.Line {
+				group = append([]extractedLine{decl}, group...)
+			} else if ok {
+				group = append(group, decl)
+			}
+		}
+		groups = append(groups, group)
+		i = end
+	}
+
+	width := 0
+	if len(groups) > 0 {
+		last := groups[len(groups)-1]
+		width = len(strconv.Itoa(last[len(last)-1].number))
+	}
+
+	var out strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		for _, line := range group {
+			if extract.LineNumbers {
+				fmt.Fprintf(&out, "%*d: ", width, line.number)
+			}
+			out.WriteString(line.text + "\n")
+		}
+	}
+	return out.String(), err
+}
+
+func isTrailingSpan(code string, span Span) bool {
+	lineStart := strings.LastIndexByte(code[:span.Start.Offset], '\n') + 1
+	return strings.TrimSpace(code[lineStart:span.Start.Offset]) != ""
+}
+
+func continuesGroup(code string, prev, next Span) bool {
+	gap := code[prev.End.Offset:next.Start.Offset]
+	return strings.TrimSpace(gap) == "" && strings.Count(gap, "\n") <= 1 && !isTrailingSpan(code, next)
+}
+
+func spanLines(span Span) []extractedLine {
+	indent := span.Start.Column - 1
+	var lines []extractedLine
+	for i, text := range strings.Split(strings.TrimRight(span.Text, " \t\r\n"), "\n") {
+		if i > 0 {
+			text = trimLeadingSpace(text, indent)
+		}
+		lines = append(lines, extractedLine{number: span.Start.Line + i, text: strings.TrimRight(text, " \t\r")})
+	}
+	return lines
+}
+
+func trimLeadingSpace(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return line[i:]
+}
+
+func annotatedLine(code string, first, last Span, limit int) (extractedLine, bool) {
+	if isTrailingSpan(code, first) {
+		lineStart := strings.LastIndexByte(code[:first.Start.Offset], '\n') + 1
+		return extractedLine{number: first.Start.Line, text: strings.TrimSpace(code[lineStart:first.Start.Offset])}, true
+	}
+
+	if first.Kind == Docstring {
+		lines := strings.Split(code[:first.Start.Offset], "\n")
+		for i := len(lines) - 2; i >= 0; i-- {
+			if text := strings.TrimSpace(lines[i]); text != "" {
+				return extractedLine{number: i + 1, text: text}, strings.HasSuffix(text, ":")
+			}
+		}
+		return extractedLine{}, false
+	}
+
+	number := last.End.Line
+	rest := code[last.End.Offset:limit]
+	for {
+		lineEnd := strings.IndexByte(rest, '\n')
+		line := rest
+		if lineEnd != -1 {
+			line = rest[:lineEnd]
+		}
+		if text := strings.TrimSpace(line); text != "" {
+			return extractedLine{number: number, text: text}, strings.Trim(text, "})];,") != ""
+		}
+		if lineEnd == -1 {
+			return extractedLine{}, false
+		}
+		rest = rest[lineEnd+1:]
+		number++
+	}
+}
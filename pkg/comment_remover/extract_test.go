@@ -0,0 +1,54 @@
+package commentremover
+
+import "testing"
+
+func TestExtract(t *testing.T) {
+	const goSource = "package main\n\n// Foo does things.\n// More.\nfunc Foo() {\n\tx := 1 // trailing\n\t/* block\n\t   second */\n\n\t// lone\n}\n\n// end\n"
+
+	tests := []struct {
+		name     string
+		language string
+		options  ExtractOptions
+		input    string
+		want     string
+	}{
+		{
+			name:     "comments only",
+			language: "go",
+			input:    goSource,
+			want:     "// Foo does things.\n// More.\n\n// trailing\n\n/* block\n   second */\n\n// lone\n\n// end\n",
+		},
+		{
+			name:     "line numbers and declarations",
+			language: "go",
+			options:  ExtractOptions{LineNumbers: true, Declarations: true},
+			input:    goSource,
+			want:     " 3: // Foo does things.\n 4: // More.\n 5: func Foo() {\n\n 6: // trailing\n 6: x := 1\n\n 7: /* block\n 8:    second */\n\n10: // lone\n\n13: // end\n",
+		},
+		{
+			name:     "docstring annotates its definition",
+			language: "python",
+			options:  ExtractOptions{LineNumbers: true, Declarations: true},
+			input:    "def f():\n    \"\"\"Doc.\"\"\"\n    return 1  # one\n",
+			want:     "1: def f():\n2: \"\"\"Doc.\"\"\"\n\n3: # one\n3: return 1\n",
+		},
+		{
+			name:     "no comments",
+			language: "go",
+			input:    "x := 1\n",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.input, DefaultOptions(tt.language), tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Extract(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
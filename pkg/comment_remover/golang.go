@@ -0,0 +1,132 @@
+package commentremover
+
+import (
+	"go/scanner"
+	gotoken "go/token"
+	"strings"
+)
+
+func lexGo(src string) ([]token, bool) {
+	fset := gotoken.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	errorCount := 0
+	var s scanner.Scanner
+	s.Init(file, []byte(src), func(gotoken.Position, string) { errorCount++ }, scanner.ScanComments)
+
+	var tokens []token
+	importStart := -1
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == gotoken.EOF {
+			break
+		}
+
+		start := file.Offset(pos)
+		switch {
+		case tok == gotoken.IMPORT:
+			importStart = start
+		case tok == gotoken.STRING && lit == `"C"` && importStart != -1:
+			keepCgoPreamble(src, tokens, importStart)
+		}
+		if tok != gotoken.IMPORT && tok != gotoken.LPAREN && tok != gotoken.COMMENT {
+			importStart = -1
+		}
+
+		switch tok {
+		case gotoken.COMMENT:
+			kind := blockCommentToken
+			if strings.HasPrefix(lit, "//") {
+				kind = lineCommentToken
+			}
+			tokens = append(tokens, token{kind: kind, start: start, end: goCommentEnd(src, start)})
+		case gotoken.STRING, gotoken.CHAR:
+			tokens = append(tokens, token{kind: stringToken, start: start, end: goLiteralEnd(src, start, lit)})
+		}
+	}
+
+	if errorCount > 0 {
+		return nil, false
+	}
+	return tokens, true
+}
+
+func keepCgoPreamble(src string, tokens []token, importStart int) {
+	next := importStart
+	for i := len(tokens) - 1; i >= 0; i-- {
+		t := tokens[i]
+		if !t.isComment() || t.end > next {
+			continue
+		}
+
+		gap := src[t.end:next]
+		if strings.TrimSpace(gap) != "" || strings.Count(gap, "\n") > 1 {
+			return
+		}
+
+		tokens[i].keep = true
+		next = t.start
+	}
+}
+
+func goCommentEnd(src string, start int) int {
+	l := &lexer{src: src, pos: start, syntax: syntaxFor("go")}
+	if strings.HasPrefix(src[start:], "//") {
+		return l.lineEnd(start)
+	}
+	return l.blockEnd(cBlock)
+}
+
+func goLiteralEnd(src string, start int, lit string) int {
+	if src[start] != '`' {
+		return start + len(lit)
+	}
+
+	idx := strings.IndexByte(src[start+1:], '`')
+	if idx == -1 {
+		return len(src)
+	}
+	return start + idx + 2
+}
+
+var goDeclKeywords = []string{"package", "import", "func", "type", "var", "const"}
+
+func markGoDocComments(src string, tokens []token) {
+	for i := 0; i < len(tokens); {
+		if !tokens[i].isComment() || strings.TrimSpace(src[strings.LastIndexByte(src[:tokens[i].start], '\n')+1:tokens[i].start]) != "" {
+			i++
+			continue
+		}
+
+		j := i
+		for j+1 < len(tokens) && tokens[j+1].isComment() {
+			gap := src[tokens[j].end:tokens[j+1].start]
+			if strings.TrimSpace(gap) != "" || strings.Count(gap, "\n") > 1 {
+				break
+			}
+			j++
+		}
+
+		if goDeclarationFollows(src[tokens[j].end:]) {
+			for k := i; k <= j; k++ {
+				tokens[k].kind = docCommentToken
+			}
+		}
+		i = j + 1
+	}
+}
+
+func goDeclarationFollows(rest string) bool {
+	newline := strings.IndexByte(rest, '\n')
+	if newline == -1 || strings.TrimSpace(rest[:newline]) != "" {
+		return false
+	}
+
+	next := strings.TrimLeft(rest[newline+1:], " \t")
+	for _, keyword := range goDeclKeywords {
+		if strings.HasPrefix(next, keyword) && (len(next) == len(keyword) || !isIdentByte(next[len(keyword)])) {
+			return true
+		}
+	}
+	return false
+}
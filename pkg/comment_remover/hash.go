@@ -0,0 +1,455 @@
+package commentremover
+
+import (
+	"strings"
+)
+
+func scanShell(l *lexer) bool {
+	switch c := l.src[l.pos]; {
+	case c == '\\':
+		l.pos = min(l.pos+2, len(l.src))
+	case c == '#':
+		if !shellWordStart(l) {
+			return false
+		}
+		l.emit(lineCommentToken, l.lineEnd(l.pos))
+	case l.hasPrefix("$#"):
+		l.pos += 2
+	case l.hasPrefix("${"):
+		l.pos = braceEnd(l.src, l.pos+2)
+	case l.hasPrefix("$'"):
+		l.pos++
+		l.emit(stringToken, l.stringEnd(stringRule{open: "'", close: "'", escape: '\\', multiline: true}))
+	case c == '\'':
+		l.emit(stringToken, l.stringEnd(stringRule{open: "'", close: "'", multiline: true}))
+	case c == '"' || c == '`':
+		l.emit(stringToken, l.stringEnd(stringRule{open: string(c), close: string(c), escape: '\\', multiline: true}))
+	case l.hasPrefix("<<") && !l.hasPrefix("<<<"):
+		return scanHeredoc(l, "-", true)
+	default:
+		return false
+	}
+	return true
+}
+
+func shellWordStart(l *lexer) bool {
+	return l.pos == 0 || strings.IndexByte(" \t\r\n;|&()", l.src[l.pos-1]) >= 0
+}
+
+func braceEnd(src string, i int) int {
+	depth := 1
+	for i < len(src) {
+		switch src[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+		i++
+	}
+	return len(src)
+}
+
+func scanHeredoc(l *lexer, indentMarks string, allowSpace bool) bool {
+	i := l.pos + 2
+	indented := false
+	if i < len(l.src) && strings.IndexByte(indentMarks, l.src[i]) >= 0 {
+		indented = true
+		i++
+	}
+
+	spaced := false
+	for allowSpace && i < len(l.src) && (l.src[i] == ' ' || l.src[i] == '\t') {
+		spaced = true
+		i++
+	}
+
+	quote := byte(0)
+	if i < len(l.src) && (l.src[i] == '\'' || l.src[i] == '"') {
+		quote = l.src[i]
+		i++
+	}
+
+	j := i
+	for j < len(l.src) && isIdentByte(l.src[j]) {
+		j++
+	}
+	if j == i || l.src[i] >= '0' && l.src[i] <= '9' {
+		return false
+	}
+
+	delim := l.src[i:j]
+	if spaced && quote == 0 && strings.ToUpper(delim) != delim {
+		return false
+	}
+	if quote != 0 {
+		if j >= len(l.src) || l.src[j] != quote {
+			return false
+		}
+		j++
+	}
+
+	l.pos = j
+	l.afterLine = append(l.afterLine, func(l *lexer) {
+		l.emit(stringToken, heredocEnd(l.src, l.pos, delim, indented))
+	})
+	return true
+}
+
+func heredocEnd(src string, from int, delim string, indented bool) int {
+	pos := from
+	for pos < len(src) {
+		lineEnd := len(src)
+		if idx := strings.IndexByte(src[pos:], '\n'); idx != -1 {
+			lineEnd = pos + idx
+		}
+
+		line := strings.TrimRight(src[pos:lineEnd], "\r")
+		if indented {
+			line = strings.TrimLeft(line, " \t")
+		}
+		if line == delim {
+			return lineEnd
+		}
+
+		pos = lineEnd + 1
+	}
+	return len(src)
+}
+
+func lineBlockEnd(src string, from int, isEnd func(line string) bool) int {
+	pos := from
+	for pos < len(src) {
+		lineEnd := len(src)
+		if idx := strings.IndexByte(src[pos:], '\n'); idx != -1 {
+			lineEnd = pos + idx
+		}
+
+		if pos != from && isEnd(strings.TrimRight(src[pos:lineEnd], "\r")) {
+			return lineEnd
+		}
+
+		pos = lineEnd + 1
+	}
+	return len(src)
+}
+
+func scanRuby(l *lexer) bool {
+	atLineStart := l.lineStart(l.pos) == l.pos
+
+	switch c := l.src[l.pos]; {
+	case atLineStart && l.hasPrefix("=begin"):
+		l.emit(blockCommentToken, lineBlockEnd(l.src, l.pos, func(line string) bool {
+			return line == "=end" || strings.HasPrefix(line, "=end ")
+		}))
+	case atLineStart && l.hasPrefix("__END__"):
+		l.emitOpenEnded(stringToken, len(l.src))
+	case c == '\\':
+		l.pos = min(l.pos+2, len(l.src))
+	case c == '#':
+		l.emit(lineCommentToken, l.lineEnd(l.pos))
+	case c == '"' || c == '`':
+		l.emit(stringToken, interpolatedEnd(l.src, l.pos+1, c))
+	case c == '\'':
+		l.emit(stringToken, l.stringEnd(stringRule{open: "'", close: "'", escape: '\\', multiline: true}))
+	case c == '%':
+		return scanPercentLiteral(l)
+	case l.hasPrefix("<<"):
+		return scanHeredoc(l, "~-", false)
+	default:
+		return false
+	}
+	return true
+}
+
+func interpolatedEnd(src string, i int, quote byte) int {
+	for i < len(src) {
+		switch {
+		case src[i] == '\\':
+			i += 2
+		case src[i] == quote:
+			return i + 1
+		case strings.HasPrefix(src[i:], "#{"):
+			i = interpolationEnd(src, i+2)
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+func interpolationEnd(src string, i int) int {
+	depth := 1
+	for i < len(src) {
+		switch src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case '"', '`':
+			i = interpolatedEnd(src, i+1, src[i])
+			continue
+		case '\'':
+			i = quotedEnd(src, i+1, '\'')
+			continue
+		}
+		i++
+	}
+	return len(src)
+}
+
+func quotedEnd(src string, i int, quote byte) int {
+	for i < len(src) {
+		switch src[i] {
+		case '\\':
+			i += 2
+		case quote:
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+var closingBracket = map[byte]byte{'(': ')', '[': ']', '{': '}', '<': '>'}
+
+func scanPercentLiteral(l *lexer) bool {
+	i := l.pos + 1
+	if i < len(l.src) && strings.IndexByte("qQwWiIrsx", l.src[i]) >= 0 {
+		i++
+	} else if prev := previousSignificant(l.src, l.pos); prev != 0 && strings.IndexByte("=(,[{;", prev) == -1 {
+		return false
+	}
+
+	if i >= len(l.src) {
+		return false
+	}
+	open := l.src[i]
+	if isIdentByte(open) || open == ' ' || open == '\t' || open == '\n' || open == '\r' || open == '=' {
+		return false
+	}
+
+	l.emit(stringToken, delimitedEnd(l.src, i, open))
+	return true
+}
+
+func delimitedEnd(src string, i int, open byte) int {
+	close, nests := closingBracket[open]
+	if !nests {
+		close = open
+	}
+
+	depth := 1
+	for i++; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '\\':
+			i++
+		case nests && c == open:
+			depth++
+		case c == close:
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(src)
+}
+
+func previousSignificant(src string, pos int) byte {
+	for i := pos - 1; i >= 0; i-- {
+		if c := src[i]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c
+		}
+	}
+	return 0
+}
+
+func scanPerl(l *lexer) bool {
+	atLineStart := l.lineStart(l.pos) == l.pos
+
+	switch c := l.src[l.pos]; {
+	case atLineStart && c == '=' && l.pos+1 < len(l.src) && isLetter(l.src[l.pos+1]):
+		l.emitOpenEnded(docCommentToken, lineBlockEnd(l.src, l.pos, func(line string) bool {
+			return line == "=cut" || strings.HasPrefix(line, "=cut ")
+		}))
+	case atLineStart && (l.hasPrefix("__END__") || l.hasPrefix("__DATA__")):
+		l.emitOpenEnded(stringToken, len(l.src))
+	case c == '\\':
+		l.pos = min(l.pos+2, len(l.src))
+	case c == '$' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '#':
+		l.pos += 2
+	case c == '#':
+		l.emit(lineCommentToken, l.lineEnd(l.pos))
+	case c == '"' || c == '\'' || c == '`':
+		l.emit(stringToken, l.stringEnd(stringRule{open: string(c), close: string(c), escape: '\\', multiline: true}))
+	case l.hasPrefix("<<") && !l.hasPrefix("<<<"):
+		return scanHeredoc(l, "~", false)
+	case isLetter(c):
+		return scanPerlQuoteLike(l)
+	default:
+		return false
+	}
+	return true
+}
+
+func scanPerlQuoteLike(l *lexer) bool {
+	if l.pos > 0 && (isIdentByte(l.src[l.pos-1]) || strings.IndexByte("$@%&*", l.src[l.pos-1]) >= 0) {
+		return false
+	}
+
+	j := l.pos
+	for j < len(l.src) && isIdentByte(l.src[j]) {
+		j++
+	}
+
+	parts := 0
+	switch l.src[l.pos:j] {
+	case "q", "qq", "qw", "qr", "m":
+		parts = 1
+	case "s", "tr", "y":
+		parts = 2
+	default:
+		l.pos = j
+		return true
+	}
+
+	i := j
+	for i < len(l.src) && (l.src[i] == ' ' || l.src[i] == '\t') {
+		i++
+	}
+	if i >= len(l.src) || (i > j && l.src[i] == '#') || isIdentByte(l.src[i]) || strings.IndexByte(" \t\r\n=,;)", l.src[i]) >= 0 {
+		l.pos = j
+		return true
+	}
+
+	l.pos = i
+	open := l.src[i]
+	end := delimitedEnd(l.src, i, open)
+	if parts == 2 {
+		if _, nests := closingBracket[open]; nests {
+			k := end
+			for k < len(l.src) && strings.IndexByte(" \t\r\n", l.src[k]) >= 0 {
+				k++
+			}
+			if k < len(l.src) {
+				end = delimitedEnd(l.src, k, l.src[k])
+			}
+		} else {
+			end = delimitedEnd(l.src, end-1, open)
+		}
+	}
+	l.emit(stringToken, end)
+	return true
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func scanYAML(l *lexer) bool {
+	switch c := l.src[l.pos]; {
+	case c == '#':
+		if l.pos > 0 && strings.IndexByte(" \t\r\n", l.src[l.pos-1]) == -1 {
+			return false
+		}
+		l.emit(lineCommentToken, l.lineEnd(l.pos))
+	case (c == '\'' || c == '"') && yamlScalarStart(l):
+		if c == '"' {
+			l.emit(stringToken, quotedEnd(l.src, l.pos+1, '"'))
+		} else {
+			l.emit(stringToken, l.stringEnd(stringRule{open: "'", close: "'", doubled: true, multiline: true}))
+		}
+	case (c == '|' || c == '>') && yamlScalarStart(l):
+		return scanYAMLBlockScalar(l)
+	default:
+		return false
+	}
+	return true
+}
+
+func yamlScalarStart(l *lexer) bool {
+	i := l.pos - 1
+	for i >= 0 && (l.src[i] == ' ' || l.src[i] == '\t') {
+		i--
+	}
+	return i < 0 || strings.IndexByte("\n:-?[{,", l.src[i]) >= 0
+}
+
+func scanYAMLBlockScalar(l *lexer) bool {
+	i := l.pos + 1
+	for i < len(l.src) && strings.IndexByte("+-0123456789", l.src[i]) >= 0 {
+		i++
+	}
+	if i < len(l.src) && strings.IndexByte(" \t\r\n#", l.src[i]) == -1 {
+		return false
+	}
+
+	start := l.lineStart(l.pos)
+	indent := len(l.src[start:l.pos]) - len(strings.TrimLeft(l.src[start:l.pos], " "))
+
+	l.pos = i
+	l.afterLine = append(l.afterLine, func(l *lexer) {
+		end := l.pos
+		for pos := l.pos; pos < len(l.src); {
+			lineEnd := len(l.src)
+			if idx := strings.IndexByte(l.src[pos:], '\n'); idx != -1 {
+				lineEnd = pos + idx
+			}
+
+			line := strings.TrimRight(l.src[pos:lineEnd], "\r")
+			if strings.TrimSpace(line) != "" {
+				if len(line)-len(strings.TrimLeft(line, " ")) <= indent {
+					break
+				}
+				end = lineEnd
+			}
+
+			pos = lineEnd + 1
+		}
+		l.emit(stringToken, end)
+	})
+	return true
+}
+
+func scanDockerfile(l *lexer) bool {
+	if l.src[l.pos] != '#' {
+		return false
+	}
+
+	if !l.onlySpaceBefore(l.pos) {
+		l.pos++
+		return true
+	}
+
+	l.emit(lineCommentToken, l.lineEnd(l.pos))
+	return true
+}
+
+func scanMakefile(l *lexer) bool {
+	recipe := l.src[l.lineStart(l.pos)] == '\t'
+
+	switch c := l.src[l.pos]; {
+	case c == '\\':
+		l.pos = min(l.pos+2, len(l.src))
+	case c == '#':
+		if recipe && !shellWordStart(l) {
+			return false
+		}
+		l.emit(lineCommentToken, l.lineEnd(l.pos))
+	case recipe && (c == '\'' || c == '"'):
+		l.emit(stringToken, l.stringEnd(stringRule{open: string(c), close: string(c), escape: '\\'}))
+	default:
+		return false
+	}
+	return true
+}
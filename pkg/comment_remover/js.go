@@ -0,0 +1,205 @@
+package commentremover
+
+import "strings"
+
+var jsExpressionKeywords = map[string]bool{
+	"return":     true,
+	"typeof":     true,
+	"instanceof": true,
+	"in":         true,
+	"of":         true,
+	"new":        true,
+	"delete":     true,
+	"void":       true,
+	"throw":      true,
+	"case":       true,
+	"do":         true,
+	"else":       true,
+	"yield":      true,
+	"await":      true,
+}
+
+func jsExpressionStart(l *lexer) bool {
+	i := l.prevCode(l.pos)
+	if i < 0 {
+		return true
+	}
+
+	switch c := l.src[i]; {
+	case isIdentByte(c) || c == '$':
+		j := i
+		for j >= 0 && (isIdentByte(l.src[j]) || l.src[j] == '$') {
+			j--
+		}
+		return jsExpressionKeywords[l.src[j+1:i+1]] && (j < 0 || l.src[j] != '.')
+	case c == ')' || c == ']' || c == '"' || c == '\'' || c == '`':
+		return false
+	case (c == '+' || c == '-') && i > 0 && l.src[i-1] == c:
+		return false
+	}
+	return true
+}
+
+func scanJavaScript(l *lexer, jsx bool, typescript bool) bool {
+	switch l.src[l.pos] {
+	case '`':
+		lexTemplate(l)
+		return true
+	case '/':
+		return scanRegex(l)
+	case '<':
+		return jsx && scanJSXElement(l, typescript)
+	}
+	return false
+}
+
+func lexTemplate(l *lexer) {
+	i := l.pos + 1
+	for i < len(l.src) {
+		switch {
+		case l.src[i] == '\\':
+			i += 2
+		case l.src[i] == '`':
+			l.emit(stringToken, i+1)
+			return
+		case strings.HasPrefix(l.src[i:], "${"):
+			l.emit(stringToken, i+2)
+			if !lexUntilBrace(l) {
+				return
+			}
+			i = l.pos + 1
+		default:
+			i++
+		}
+	}
+	l.emit(stringToken, len(l.src))
+}
+
+func scanRegex(l *lexer) bool {
+	if l.hasPrefix("//") || l.hasPrefix("/*") || !jsExpressionStart(l) {
+		return false
+	}
+
+	inClass := false
+	for i := l.pos + 1; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\\':
+			i++
+		case '\n':
+			return false
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if inClass {
+				continue
+			}
+			i++
+			for i < len(l.src) && isLetter(l.src[i]) {
+				i++
+			}
+			l.emit(stringToken, i)
+			return true
+		}
+	}
+	return false
+}
+
+func scanJSXElement(l *lexer, typescript bool) bool {
+	if l.pos+1 >= len(l.src) {
+		return false
+	}
+	if next := l.src[l.pos+1]; !isLetter(next) && next != '>' {
+		return false
+	}
+	if !jsExpressionStart(l) || typescript && isTypeParameterList(l.src, l.pos) {
+		return false
+	}
+
+	lexJSXElement(l)
+	return true
+}
+
+func isTypeParameterList(src string, pos int) bool {
+	i := pos + 1
+	for i < len(src) && (isIdentByte(src[i]) || src[i] == '$') {
+		i++
+	}
+	for i < len(src) && isSpace(src[i]) {
+		i++
+	}
+	return i < len(src) && (src[i] == ',' || strings.HasPrefix(src[i:], "extends "))
+}
+
+func lexJSXElement(l *lexer) {
+	l.pos++
+	typeArguments := 0
+	for l.pos < len(l.src) {
+		switch {
+		case l.hasPrefix("/>"):
+			l.pos += 2
+			return
+		case l.src[l.pos] == '<':
+			typeArguments++
+			l.pos++
+		case l.src[l.pos] == '>' && typeArguments > 0:
+			typeArguments--
+			l.pos++
+		case l.src[l.pos] == '>':
+			l.pos++
+			lexJSXChildren(l)
+			return
+		case l.src[l.pos] == '"' || l.src[l.pos] == '\'':
+			l.emit(stringToken, indexEnd(l.src, l.pos+1, l.src[l.pos:l.pos+1]))
+		case l.src[l.pos] == '{':
+			lexJSXContainer(l, false)
+		case !l.lexLineComment() && !l.lexBlockComment():
+			l.pos++
+		}
+	}
+}
+
+func lexJSXChildren(l *lexer) {
+	for l.pos < len(l.src) {
+		switch {
+		case l.hasPrefix("</"):
+			l.pos = indexEnd(l.src, l.pos+2, ">")
+			return
+		case l.src[l.pos] == '<' && l.pos+1 < len(l.src) && (isLetter(l.src[l.pos+1]) || l.src[l.pos+1] == '>'):
+			lexJSXElement(l)
+		case l.src[l.pos] == '{':
+			lexJSXContainer(l, true)
+		default:
+			l.pos++
+		}
+	}
+}
+
+func lexJSXContainer(l *lexer, child bool) {
+	start := l.pos
+	first := len(l.tokens)
+
+	l.pos++
+	if !lexUntilBrace(l) {
+		return
+	}
+	l.pos++
+
+	if !child || first == len(l.tokens) {
+		return
+	}
+
+	prev := start + 1
+	for _, t := range l.tokens[first:] {
+		if !t.isComment() || strings.TrimSpace(l.src[prev:t.start]) != "" {
+			return
+		}
+		prev = t.end
+	}
+	if strings.TrimSpace(l.src[prev:l.pos-1]) != "" {
+		return
+	}
+
+	l.tokens = append(l.tokens[:first], token{kind: jsxCommentToken, start: start, end: l.pos})
+}
@@ -0,0 +1,43 @@
+package commentremover
+
+func scanKotlin(l *lexer) bool {
+	switch {
+	case l.hasPrefix(`"""`):
+		lexKotlinString(l, `"""`)
+	case l.hasPrefix(`"`):
+		lexKotlinString(l, `"`)
+	default:
+		return false
+	}
+	return true
+}
+
+func lexKotlinString(l *lexer, quote string) {
+	raw := len(quote) == 3
+	i := l.pos + len(quote)
+	for i < len(l.src) {
+		switch {
+		case !raw && l.src[i] == '\\':
+			i += 2
+		case l.src[i:min(i+len(quote), len(l.src))] == quote:
+			end := i + len(quote)
+			for raw && end < len(l.src) && l.src[end] == '"' {
+				end++
+			}
+			l.emit(stringToken, end)
+			return
+		case l.src[i] == '$' && i+1 < len(l.src) && l.src[i+1] == '{':
+			l.emit(stringToken, i+2)
+			if !lexUntilBrace(l) {
+				return
+			}
+			i = l.pos + 1
+		case !raw && l.src[i] == '\n':
+			l.emit(stringToken, i)
+			return
+		default:
+			i++
+		}
+	}
+	l.emit(stringToken, len(l.src))
+}
@@ -0,0 +1,268 @@
+package commentremover
+
+import "strings"
+
+type tokenKind int
+
+const (
+	stringToken tokenKind = iota
+	lineCommentToken
+	blockCommentToken
+	docCommentToken
+	jsxCommentToken
+	docstringToken
+	bareStringToken
+	deadCodeToken
+)
+
+type token struct {
+	kind      tokenKind
+	start     int
+	end       int
+	keep      bool
+	openEnded bool
+}
+
+func (t token) isComment() bool {
+	switch t.kind {
+	case lineCommentToken, blockCommentToken, docCommentToken, jsxCommentToken, docstringToken:
+		return true
+	}
+	return false
+}
+
+type delimiter struct {
+	open  string
+	close string
+}
+
+type stringRule struct {
+	open      string
+	close     string
+	escape    byte
+	doubled   bool
+	multiline bool
+}
+
+type syntax struct {
+	lineComments     []string
+	lineContinuation bool
+	blockComments    []delimiter
+	nestedComments   bool
+	docComments      []string
+	strings          []stringRule
+	scan             func(l *lexer) bool
+}
+
+type lexer struct {
+	src       string
+	pos       int
+	syntax    *syntax
+	tokens    []token
+	afterLine []func(l *lexer)
+	brackets  int
+}
+
+func lex(src string, s *syntax) []token {
+	l := &lexer{src: src, syntax: s}
+
+	for l.pos < len(l.src) {
+		l.step()
+	}
+
+	return l.tokens
+}
+
+func (l *lexer) step() {
+	if l.src[l.pos] == '\n' && len(l.afterLine) > 0 {
+		l.pos++
+		pending := l.afterLine
+		l.afterLine = nil
+		for _, fn := range pending {
+			fn(l)
+		}
+		return
+	}
+
+	if l.syntax.scan != nil && l.syntax.scan(l) {
+		return
+	}
+
+	if l.lexLineComment() || l.lexBlockComment() || l.lexString() {
+		return
+	}
+
+	l.pos++
+}
+
+func lexUntilBrace(l *lexer) bool {
+	depth := 0
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '{':
+			depth++
+			l.pos++
+		case '}':
+			if depth == 0 {
+				return true
+			}
+			depth--
+			l.pos++
+		default:
+			l.step()
+		}
+	}
+	return false
+}
+
+func (l *lexer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(l.src[l.pos:], prefix)
+}
+
+func (l *lexer) emit(kind tokenKind, end int) {
+	l.tokens = append(l.tokens, token{kind: kind, start: l.pos, end: end})
+	l.pos = end
+}
+
+func (l *lexer) emitOpenEnded(kind tokenKind, end int) {
+	l.emit(kind, end)
+	l.tokens[len(l.tokens)-1].openEnded = true
+}
+
+func (l *lexer) lexLineComment() bool {
+	for _, prefix := range l.syntax.lineComments {
+		if l.hasPrefix(prefix) {
+			for _, d := range l.syntax.blockComments {
+				if len(d.open) > len(prefix) && l.hasPrefix(d.open) {
+					return false
+				}
+			}
+			end := l.lineEnd(l.pos)
+			for l.syntax.lineContinuation && end > l.pos && l.src[end-1] == '\\' && end < len(l.src) {
+				end = l.lineEnd(end + 1)
+			}
+			l.emit(lineCommentToken, end)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lexer) lexBlockComment() bool {
+	for _, d := range l.syntax.blockComments {
+		if l.hasPrefix(d.open) {
+			l.emit(blockCommentToken, l.blockEnd(d))
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lexer) lexString() bool {
+	for _, rule := range l.syntax.strings {
+		if l.hasPrefix(rule.open) {
+			l.emit(stringToken, l.stringEnd(rule))
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lexer) lineEnd(from int) int {
+	idx := strings.IndexByte(l.src[from:], '\n')
+	if idx == -1 {
+		return len(l.src)
+	}
+
+	end := from + idx
+	if end > from && l.src[end-1] == '\r' {
+		end--
+	}
+	return end
+}
+
+func (l *lexer) blockEnd(d delimiter) int {
+	if !l.syntax.nestedComments {
+		from := l.pos + len(d.open)
+		idx := strings.Index(l.src[from:], d.close)
+		if idx == -1 {
+			return len(l.src)
+		}
+		return from + idx + len(d.close)
+	}
+
+	depth := 0
+	i := l.pos
+	for i < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[i:], d.open):
+			depth++
+			i += len(d.open)
+		case strings.HasPrefix(l.src[i:], d.close):
+			depth--
+			i += len(d.close)
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+
+	return len(l.src)
+}
+
+func (l *lexer) stringEnd(rule stringRule) int {
+	i := l.pos + len(rule.open)
+
+	for i < len(l.src) {
+		switch {
+		case rule.escape != 0 && l.src[i] == rule.escape:
+			i += 2
+		case rule.doubled && strings.HasPrefix(l.src[i:], rule.close+rule.close):
+			i += 2 * len(rule.close)
+		case strings.HasPrefix(l.src[i:], rule.close):
+			return i + len(rule.close)
+		case l.src[i] == '\n' && !rule.multiline:
+			return i
+		default:
+			i++
+		}
+	}
+
+	return len(l.src)
+}
+
+func (l *lexer) lineStart(pos int) int {
+	return strings.LastIndexByte(l.src[:pos], '\n') + 1
+}
+
+func (l *lexer) prevCode(pos int) int {
+	t := len(l.tokens) - 1
+	for i := pos - 1; i >= 0; {
+		for t >= 0 && l.tokens[t].start > i {
+			t--
+		}
+		if t >= 0 && l.tokens[t].end > i && l.tokens[t].kind != stringToken && l.tokens[t].kind != docstringToken && l.tokens[t].kind != bareStringToken {
+			i = l.tokens[t].start - 1
+			continue
+		}
+		if !isSpace(l.src[i]) {
+			return i
+		}
+		i--
+	}
+	return -1
+}
+
+func (l *lexer) onlySpaceBefore(pos int) bool {
+	return strings.TrimSpace(l.src[l.lineStart(pos):pos]) == ""
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
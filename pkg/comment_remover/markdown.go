@@ -0,0 +1,198 @@
+package commentremover
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+var (
+	fenceOpenPattern = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})\\s*([^\\s`]*)")
+	listItemPattern  = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+)
+
+const DefaultMinConfidence = 0.5
+
+type BlockDetection struct {
+	Line int
+	Detection
+}
+
+func openingFence(line string) []string {
+	m := fenceOpenPattern.FindStringSubmatch(line)
+	if m == nil || m[2][0] == '`' && strings.Contains(line[len(m[0]):], "`") {
+		return nil
+	}
+	return m
+}
+
+func TransformMarkdown(text string, language string, minConfidence float64, transform func(code, language string) (string, error)) (string, []BlockDetection, error) {
+	lines := strings.Split(text, "\n")
+	var out []string
+	var detections []BlockDetection
+	var errs []error
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if m := openingFence(line); m != nil {
+			indent, fence, info := m[1], m[2], m[3]
+			end := i + 1
+			for end < len(lines) && !closesFence(lines[end], fence) {
+				end++
+			}
+
+			out = append(out, line)
+			body := make([]string, 0, end-i-1)
+			for _, l := range lines[i+1 : end] {
+				body = append(body, trimIndent(l, len(indent)))
+			}
+			cleaned, detection, err := transformBlock(body, info, language, minConfidence, transform)
+			if detection != nil {
+				detections = append(detections, BlockDetection{Line: i + 1, Detection: *detection})
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("code block at line %d: %w", i+1, err))
+			}
+			if slices.Equal(cleaned, body) {
+				out = append(out, lines[i+1:end]...)
+			} else {
+				for _, l := range cleaned {
+					out = append(out, indentLine(l, indent))
+				}
+			}
+			if end < len(lines) {
+				out = append(out, lines[end])
+			}
+			i = end + 1
+			continue
+		}
+
+		if isIndentedCode(line) && startsIndentedBlock(lines, i) {
+			end := i
+			for end < len(lines) && (isIndentedCode(lines[end]) || strings.TrimSpace(lines[end]) == "" && end+1 < len(lines) && isIndentedCode(lines[end+1])) {
+				end++
+			}
+
+			body := make([]string, 0, end-i)
+			units := make([]string, 0, end-i)
+			for _, l := range lines[i:end] {
+				unit := indentUnit(l)
+				body = append(body, strings.TrimPrefix(l, unit))
+				units = append(units, unit)
+			}
+			cleaned, detection, err := transformBlock(body, "", language, minConfidence, transform)
+			if detection != nil {
+				detections = append(detections, BlockDetection{Line: i + 1, Detection: *detection})
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("code block at line %d: %w", i+1, err))
+			}
+			if slices.Equal(cleaned, body) {
+				out = append(out, lines[i:end]...)
+			} else {
+				for j, l := range cleaned {
+					unit := units[0]
+					if len(cleaned) == len(units) && units[j] != "" {
+						unit = units[j]
+					}
+					out = append(out, indentLine(l, unit))
+				}
+			}
+			i = end
+			continue
+		}
+
+		out = append(out, line)
+		i++
+	}
+
+	return strings.Join(out, "\n"), detections, errors.Join(errs...)
+}
+
+func transformBlock(body []string, info string, language string, minConfidence float64, transform func(code, language string) (string, error)) ([]string, *Detection, error) {
+	if info != "" {
+		lang, ok := languages.Lookup(info)
+		if !ok {
+			return body, nil, nil
+		}
+		language = lang.Name
+	}
+
+	code := strings.Join(body, "\n")
+	var detection *Detection
+	if language == languages.Auto {
+		detected := DetectLanguage(code)
+		detection = &detected
+		if detected.Language == "" || detected.Confidence < minConfidence {
+			return body, detection, nil
+		}
+		language = detected.Language
+	}
+
+	transformed, err := transform(code+"\n", language)
+	transformed = strings.TrimSuffix(transformed, "\n")
+	if transformed == "" {
+		return nil, detection, err
+	}
+	return strings.Split(transformed, "\n"), detection, err
+}
+
+func closesFence(line string, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	run := len(trimmed) - len(strings.TrimLeft(trimmed, fence[:1]))
+	return run >= len(fence) && strings.TrimSpace(trimmed[run:]) == ""
+}
+
+func trimIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return line[i:]
+}
+
+func indentUnit(line string) string {
+	switch {
+	case strings.HasPrefix(line, "\t"):
+		return "\t"
+	case strings.HasPrefix(line, "    "):
+		return "    "
+	}
+	return ""
+}
+
+func indentLine(line string, indent string) string {
+	if strings.TrimSpace(line) == "" {
+		return line
+	}
+	return indent + line
+}
+
+func isIndentedCode(line string) bool {
+	return (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) && strings.TrimSpace(line) != ""
+}
+
+func startsIndentedBlock(lines []string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	if strings.TrimSpace(lines[i-1]) != "" {
+		return false
+	}
+
+	for j := i - 1; j >= 0; j-- {
+		if strings.TrimSpace(lines[j]) == "" {
+			continue
+		}
+		return !listItemPattern.MatchString(lines[j]) && !isIndentedCode(lines[j]) && !strings.HasPrefix(lines[j], "  ")
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package commentremover
+
+import (
+	"testing"
+)
+
+func TestTransformMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		rules    []string
+		input    string
+		want     string
+	}{
+		{
+			name:     "headings and prose are left alone",
+			language: "python",
+			input:    "# Title\n\nSome prose # not a comment\n\n```python\n# c\nx = 1  # t\n```\n",
+			want:     "# Title\n\nSome prose # not a comment\n\n```python\nx = 1\n```\n",
+		},
+		{
+			name:     "info string picks the language",
+			language: "python",
+			input:    "```go\n// c\nx := 1 // t\n```\n\n~~~~js\nlet q = 1; // c\n~~~~\n",
+			want:     "```go\nx := 1\n```\n\n~~~~js\nlet q = 1;\n~~~~\n",
+		},
+		{
+			name:     "unknown info string is untouched",
+			language: "go",
+			input:    "```text\n// keep\n```\n",
+			want:     "```text\n// keep\n```\n",
+		},
+		{
+			name:     "indented fence",
+			language: "go",
+			input:    "  ```python title=\"x\"\n  # comment\n  y = 2\n  ```\n",
+			want:     "  ```python title=\"x\"\n  y = 2\n  ```\n",
+		},
+		{
+			name:     "unlabelled fence uses the selected language",
+			language: "c",
+			input:    "```\n/* c */ z();\n```\n",
+			want:     "```\nz();\n```\n",
+		},
+		{
+			name:     "indented code block",
+			language: "go",
+			input:    "Indented:\n\n    // indented comment\n    a := 1\n",
+			want:     "Indented:\n\n    a := 1\n",
+		},
+		{
+			name:     "tab-indented code block keeps its indentation",
+			language: "go",
+			input:    "Tabs:\n\n\tfunc f() {\n\t    x := 1 // c\n\t}\n",
+			want:     "Tabs:\n\n\tfunc f() {\n\t    x := 1\n\t}\n",
+		},
+		{
+			name:     "untouched code block is left as it is",
+			language: "go",
+			input:    "Mixed:\n\n\tx := 1\n     y := 2\n",
+			want:     "Mixed:\n\n\tx := 1\n     y := 2\n",
+		},
+		{
+			name:     "inline triple backticks are not a fence",
+			language: "go",
+			input:    "inline ```go``` x // not code\n",
+			want:     "inline ```go``` x // not code\n",
+		},
+		{
+			name:     "list continuation is not code",
+			language: "python",
+			input:    "- list\n\n    continuation # not code\n",
+			want:     "- list\n\n    continuation # not code\n",
+		},
+		{
+			name:     "preserve rules apply inside blocks",
+			language: "javascript",
+			rules:    []string{"re:keep"},
+			input:    "```js\na(); // keep me\nb(); // drop me\n```\n",
+			want:     "```js\na(); // keep me\nb();\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParsePreserveRules(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := TransformMarkdown(tt.input, tt.language, DefaultMinConfidence, func(code, language string) (string, error) {
+				opts := DefaultOptions(language)
+				if tt.rules != nil {
+					opts.Preserve = rules
+				}
+				result, err := Remove(code, opts)
+				return result.Code, err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("TransformMarkdown(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformMarkdownConfidence(t *testing.T) {
+	const input = "```\nx = 1 # c\n```\n"
+
+	var calls int
+	transform := func(code, language string) (string, error) {
+		calls++
+		return code, nil
+	}
+
+	if _, _, err := TransformMarkdown(input, "auto", 0.99, transform); err != nil || calls != 0 {
+		t.Errorf("transform called %d times below the threshold (err %v), want 0", calls, err)
+	}
+	if _, _, err := TransformMarkdown(input, "auto", 0, transform); err != nil || calls != 1 {
+		t.Errorf("transform called %d times with a zero threshold (err %v), want 1", calls, err)
+	}
+}
@@ -0,0 +1,115 @@
+package commentremover
+
+import (
+	"regexp"
+	"strings"
+)
+
+var attributePattern = regexp.MustCompile(`(?i)\b(type|lang)\s*=\s*["']?([^"'\s>]+)`)
+
+func scanMarkup(l *lexer, embedded bool) bool {
+	switch {
+	case l.hasPrefix("<!--"):
+		l.emit(blockCommentToken, indexEnd(l.src, l.pos+4, "-->"))
+	case l.hasPrefix("<![CDATA["):
+		l.emit(stringToken, indexEnd(l.src, l.pos+9, "]]>"))
+	case l.hasPrefix("<?") || l.hasPrefix("<!"):
+		l.pos = indexEnd(l.src, l.pos+2, ">")
+	case l.src[l.pos] == '<' && l.pos+1 < len(l.src) && isLetter(l.src[l.pos+1]):
+		scanTag(l, embedded)
+	default:
+		return false
+	}
+	return true
+}
+
+func scanTag(l *lexer, embedded bool) {
+	nameEnd := l.pos + 1
+	for nameEnd < len(l.src) && (isIdentByte(l.src[nameEnd]) || l.src[nameEnd] == '-' || l.src[nameEnd] == ':') {
+		nameEnd++
+	}
+	name := strings.ToLower(l.src[l.pos+1 : nameEnd])
+
+	end := nameEnd
+	for end < len(l.src) && l.src[end] != '>' {
+		if c := l.src[end]; c == '"' || c == '\'' {
+			end = indexEnd(l.src, end+1, string(c))
+			continue
+		}
+		end++
+	}
+	end = min(end+1, len(l.src))
+
+	attrs := l.src[nameEnd:end]
+	l.pos = end
+	if !embedded || strings.HasSuffix(attrs, "/>") {
+		return
+	}
+
+	switch name {
+	case "script", "style", "textarea", "title":
+	default:
+		return
+	}
+
+	contentEnd := len(l.src)
+	if idx := strings.Index(strings.ToLower(l.src[end:]), "</"+name); idx != -1 {
+		contentEnd = end + idx
+	}
+	if contentEnd == end {
+		return
+	}
+
+	if language := embeddedLanguage(name, attrs); language != "" {
+		for _, t := range lex(l.src[end:contentEnd], syntaxFor(language)) {
+			t.start += end
+			t.end += end
+			l.tokens = append(l.tokens, t)
+		}
+		l.pos = contentEnd
+		return
+	}
+
+	l.emit(stringToken, contentEnd)
+}
+
+func embeddedLanguage(tag string, attrs string) string {
+	kind := ""
+	for _, match := range attributePattern.FindAllStringSubmatch(attrs, -1) {
+		kind = strings.ToLower(match[2])
+	}
+
+	switch tag {
+	case "script":
+		switch kind {
+		case "", "module", "text/javascript", "application/javascript", "js", "javascript", "jsx":
+			return "javascript"
+		case "ts", "typescript":
+			return "typescript"
+		case "tsx":
+			return "tsx"
+		}
+	case "style":
+		switch kind {
+		case "", "text/css", "css":
+			return "css"
+		case "scss", "sass":
+			return "scss"
+		case "less", "text/less":
+			return "less"
+		}
+	}
+	return ""
+}
+
+func indexEnd(src string, from int, closing string) int {
+	if from > len(src) {
+		return len(src)
+	}
+
+	idx := strings.Index(src[from:], closing)
+	if idx == -1 {
+		return len(src)
+	}
+	return from + idx + len(closing)
+}
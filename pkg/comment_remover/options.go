@@ -0,0 +1,43 @@
+package commentremover
+
+import (
+	"regexp"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+var GoDirectivePrefixes = []string{
+	"//go:",
+	"// +build",
+	"//line ",
+	"/*line ",
+	"//export ",
+	"//extern ",
+}
+
+type Options struct {
+	Language          string
+	KeepDirectives    bool
+	DirectivePrefixes []string
+	Preserve          []PreserveRule
+	DropBareStrings   bool
+	StripDeadCode     bool
+	Policy            RemovalPolicy
+	Pattern           *regexp.Regexp
+	Whitespace        WhitespacePolicy
+	KeepLineNumbers   bool
+}
+
+func DefaultOptions(language string) Options {
+	opts := Options{Language: language}
+	if lang, ok := languages.Lookup(language); ok {
+		opts.Preserve, _ = ParsePreserveRules(lang.Preserve)
+	}
+
+	if languages.Canonical(language) == "go" {
+		opts.KeepDirectives = true
+		opts.DirectivePrefixes = GoDirectivePrefixes
+	}
+
+	return opts
+}
@@ -0,0 +1,147 @@
+package commentremover
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type RemovalPolicy int
+
+const (
+	RemoveAll RemovalPolicy = iota
+	KeepDocs
+	InlineOnly
+	MatchingOnly
+)
+
+type WhitespacePolicy int
+
+const (
+	PreserveBlankLines WhitespacePolicy = iota
+	CollapseBlankLines
+	RemoveBlankLines
+)
+
+var DefaultRemovalPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK|XXX)\b`)
+
+var removalPolicyNames = map[RemovalPolicy]string{
+	RemoveAll:    "all",
+	KeepDocs:     "keep-docs",
+	InlineOnly:   "inline",
+	MatchingOnly: "matching",
+}
+
+func (p RemovalPolicy) String() string {
+	if name, ok := removalPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("RemovalPolicy(%d)", int(p))
+}
+
+func ParseRemovalPolicy(name string) (RemovalPolicy, error) {
+	switch strings.TrimSpace(name) {
+	case "", "all":
+		return RemoveAll, nil
+	case "keep-docs", "docs":
+		return KeepDocs, nil
+	case "inline", "trailing":
+		return InlineOnly, nil
+	case "matching", "todo":
+		return MatchingOnly, nil
+	}
+	return RemoveAll, fmt.Errorf("unknown removal policy %q", name)
+}
+
+var whitespacePolicyNames = map[WhitespacePolicy]string{
+	PreserveBlankLines: "preserve",
+	CollapseBlankLines: "collapse",
+	RemoveBlankLines:   "remove",
+}
+
+func (p WhitespacePolicy) String() string {
+	if name, ok := whitespacePolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("WhitespacePolicy(%d)", int(p))
+}
+
+func ParseWhitespacePolicy(name string) (WhitespacePolicy, error) {
+	switch strings.TrimSpace(name) {
+	case "", "preserve":
+		return PreserveBlankLines, nil
+	case "collapse":
+		return CollapseBlankLines, nil
+	case "remove":
+		return RemoveBlankLines, nil
+	}
+	return PreserveBlankLines, fmt.Errorf("unknown blank line policy %q", name)
+}
+
+func (opts Options) remover(code string) func(token) bool {
+	pattern := opts.Pattern
+	if pattern == nil {
+		pattern = DefaultRemovalPattern
+	}
+
+	return func(t token) bool {
+		switch {
+		case t.keep:
+			return false
+		case t.kind == bareStringToken:
+			return opts.DropBareStrings
+		case t.kind == deadCodeToken:
+			return opts.StripDeadCode
+		case !t.isComment():
+			return false
+		}
+
+		switch opts.Policy {
+		case KeepDocs:
+			return t.kind != docCommentToken && t.kind != docstringToken
+		case InlineOnly:
+			return isTrailing(code, t)
+		case MatchingOnly:
+			return pattern.MatchString(code[t.start:t.end])
+		default:
+			return true
+		}
+	}
+}
+
+func isTrailing(code string, t token) bool {
+	lineStart := strings.LastIndexByte(code[:t.start], '\n') + 1
+	if strings.TrimSpace(code[lineStart:t.start]) == "" {
+		return false
+	}
+
+	rest := code[t.end:]
+	if idx := strings.IndexByte(rest, '\n'); idx != -1 {
+		rest = rest[:idx]
+	}
+	return strings.TrimSpace(rest) == ""
+}
+
+func markDocComments(code string, tokens []token, prefixes []string) {
+	for i, t := range tokens {
+		if t.kind != lineCommentToken && t.kind != blockCommentToken {
+			continue
+		}
+		if isDocComment(code[t.start:t.end], prefixes) {
+			tokens[i].kind = docCommentToken
+		}
+	}
+}
+
+func isDocComment(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(text, prefix) {
+			continue
+		}
+		rest := text[len(prefix):]
+		if rest == "" || rest[0] != '/' && rest[0] != prefix[len(prefix)-1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package commentremover
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+func TestRemovalPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		policy   RemovalPolicy
+		pattern  string
+		input    string
+		want     string
+	}{
+		{
+			name:     "keep rust doc comments",
+			language: "rust",
+			policy:   KeepDocs,
+			input:    "/// Adds.\n// plain\nfn add() {} //! inner\n",
+			want:     "/// Adds.\nfn add() {} //! inner\n",
+		},
+		{
+			name:     "keep javadoc",
+			language: "java",
+			policy:   KeepDocs,
+			input:    "/** Doc. */\n/* plain */\nclass A {}\n",
+			want:     "/** Doc. */\nclass A {}\n",
+		},
+		{
+			name:     "keep go doc comments above declarations",
+			language: "go",
+			policy:   KeepDocs,
+			input:    "// F does things.\nfunc F() {\n\t// inside\n\treturn\n}\n",
+			want:     "// F does things.\nfunc F() {\n\treturn\n}\n",
+		},
+		{
+			name:     "keep python docstrings",
+			language: "python",
+			policy:   KeepDocs,
+			input:    "def f():\n    \"\"\"Doc.\"\"\"\n    # c\n    return 1\n",
+			want:     "def f():\n    \"\"\"Doc.\"\"\"\n    return 1\n",
+		},
+		{
+			name:     "inline only",
+			language: "go",
+			policy:   InlineOnly,
+			input:    "// header\nx := 1 // trailing\n/* a */ y := 2\n",
+			want:     "// header\nx := 1\n/* a */ y := 2\n",
+		},
+		{
+			name:     "matching default pattern",
+			language: "python",
+			policy:   MatchingOnly,
+			input:    "# TODO: fix\n# explains\nx = 1  # FIXME\n",
+			want:     "# explains\nx = 1\n",
+		},
+		{
+			name:     "matching custom pattern",
+			language: "javascript",
+			policy:   MatchingOnly,
+			pattern:  "DEBUG",
+			input:    "log(); // DEBUG\n// TODO keep\n",
+			want:     "log();\n// TODO keep\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions(tt.language)
+			opts.Policy = tt.policy
+			if tt.pattern != "" {
+				opts.Pattern = regexp.MustCompile(tt.pattern)
+			}
+			if got := RemoveComments(tt.input, opts); got != tt.want {
+				t.Errorf("RemoveComments(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRemovalPolicy(t *testing.T) {
+	for name, want := range map[string]RemovalPolicy{
+		"all":       RemoveAll,
+		"keep-docs": KeepDocs,
+		"docs":      KeepDocs,
+		"inline":    InlineOnly,
+		"trailing":  InlineOnly,
+		"matching":  MatchingOnly,
+		"todo":      MatchingOnly,
+	} {
+		if got, err := ParseRemovalPolicy(name); err != nil || got != want {
+			t.Errorf("ParseRemovalPolicy(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseRemovalPolicy("bogus"); err == nil {
+		t.Error("ParseRemovalPolicy(\"bogus\") returned no error")
+	}
+}
+
+func TestRegistryPreserveRules(t *testing.T) {
+	for _, lang := range languages.All() {
+		if _, err := ParsePreserveRules(lang.Preserve); err != nil {
+			t.Errorf("%s: %v", lang.Name, err)
+		}
+	}
+
+	err := languages.Register(languages.Language{Name: "typotest", LineComments: []string{"#"}, Preserve: []string{"shebnag"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown preserve rule set "shebnag"`) {
+		t.Errorf("Register() = %v, want an unknown preserve rule set error", err)
+	}
+	if err := languages.SetPreserve("python", []string{"re:("}); err == nil {
+		t.Error("SetPreserve() accepted an invalid pattern")
+	}
+}
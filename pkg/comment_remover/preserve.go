@@ -0,0 +1,134 @@
+package commentremover
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+type PreserveRule struct {
+	Prefix  string
+	Pattern *regexp.Regexp
+}
+
+func (r PreserveRule) matches(comment string) bool {
+	if r.Pattern != nil {
+		return r.Pattern.MatchString(comment)
+	}
+	return r.Prefix != "" && strings.HasPrefix(comment, r.Prefix)
+}
+
+var spdxPattern = regexp.MustCompile(`^(//|#|/\*)\s*SPDX-License-Identifier:`)
+
+var preserveRuleSets = map[string][]PreserveRule{
+	"shebang": {
+		{Prefix: "#!"},
+	},
+	"encoding": {
+		{Pattern: regexp.MustCompile(`^#.*coding[:=]\s*[-\w.]+`)},
+	},
+	"noqa": {
+		{Pattern: regexp.MustCompile(`^#.*\bnoqa\b`)},
+	},
+	"type-ignore": {
+		{Pattern: regexp.MustCompile(`^#\s*type:\s*ignore\b`)},
+		{Pattern: regexp.MustCompile(`^#\s*pyright:\s*ignore\b`)},
+	},
+	"eslint": {
+		{Pattern: regexp.MustCompile(`^(//|/\*)\s*eslint(-disable|-enable|\s)`)},
+		{Pattern: regexp.MustCompile(`^(//|/\*)\s*global\s`)},
+	},
+	"ts-pragmas": {
+		{Pattern: regexp.MustCompile(`^//\s*@ts-(ignore|expect-error|nocheck|check)\b`)},
+	},
+	"triple-slash": {
+		{Pattern: regexp.MustCompile(`^///\s*<(reference|amd-module|amd-dependency)\b`)},
+	},
+	"license": {
+		{Prefix: "/*!"},
+		{Pattern: spdxPattern},
+	},
+	"spdx": {
+		{Pattern: spdxPattern},
+	},
+	"shellcheck": {
+		{Pattern: regexp.MustCompile(`^#\s*shellcheck\s`)},
+	},
+	"magic-comments": {
+		{Pattern: regexp.MustCompile(`^#\s*(frozen_string_literal|encoding|coding|warn_indent|shareable_constant_value):`)},
+	},
+	"rubocop": {
+		{Pattern: regexp.MustCompile(`^#\s*rubocop:(disable|enable|todo)\b`)},
+	},
+	"schema": {
+		{Pattern: regexp.MustCompile(`^#\s*(yaml-language-server:|:schema\s)`)},
+	},
+	"dockerfile-directives": {
+		{Pattern: regexp.MustCompile(`^#\s*(syntax|escape|check)=`)},
+	},
+	"sql-hints": {
+		{Pattern: regexp.MustCompile(`^/\*[!+]`)},
+	},
+	"haskell-pragmas": {
+		{Prefix: "{-#"},
+	},
+	"conditional-comments": {
+		{Pattern: regexp.MustCompile(`^<!--\s*(\[if\b|<!\[endif\])`)},
+	},
+	"nolint": {
+		{Pattern: regexp.MustCompile(`^//\s*nolint\b`)},
+		{Pattern: regexp.MustCompile(`^//lint:`)},
+	},
+}
+
+func init() {
+	languages.SetPreserveValidator(func(rules []string) error {
+		_, err := ParsePreserveRules(rules)
+		return err
+	})
+}
+
+func ParsePreserveRules(specs []string) ([]PreserveRule, error) {
+	var rules []PreserveRule
+
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		switch {
+		case spec == "":
+			continue
+		case strings.HasPrefix(spec, "re:"):
+			pattern, err := regexp.Compile(strings.TrimPrefix(spec, "re:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid preserve pattern %q: %v", spec, err)
+			}
+			rules = append(rules, PreserveRule{Pattern: pattern})
+		case strings.HasPrefix(spec, "prefix:"):
+			rules = append(rules, PreserveRule{Prefix: strings.TrimPrefix(spec, "prefix:")})
+		default:
+			set, ok := preserveRuleSets[spec]
+			if !ok {
+				return nil, fmt.Errorf("unknown preserve rule set %q", spec)
+			}
+			rules = append(rules, set...)
+		}
+	}
+
+	return rules, nil
+}
+
+func markPreserved(code string, tokens []token, rules []PreserveRule) {
+	for i, t := range tokens {
+		if !t.isComment() {
+			continue
+		}
+
+		for _, rule := range rules {
+			if rule.matches(code[t.start:t.end]) {
+				tokens[i].keep = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package commentremover
+
+import "strings"
+
+func scanPython(l *lexer) bool {
+	switch c := l.src[l.pos]; {
+	case c == '(' || c == '[' || c == '{':
+		l.brackets++
+	case c == ')' || c == ']' || c == '}':
+		l.brackets = max(0, l.brackets-1)
+	case c == '"' || c == '\'' || isLetter(c):
+		return scanPythonString(l)
+	default:
+		return false
+	}
+	l.pos++
+	return true
+}
+
+func scanPythonString(l *lexer) bool {
+	start := l.pos
+	if start > 0 && isIdentByte(l.src[start-1]) {
+		return false
+	}
+
+	i := start
+	for i < len(l.src) && i-start < 2 && strings.IndexByte("rRbBuUfFtT", l.src[i]) >= 0 {
+		i++
+	}
+	if i >= len(l.src) || l.src[i] != '"' && l.src[i] != '\'' {
+		return false
+	}
+
+	prefix := strings.ToLower(l.src[start:i])
+	quote := l.src[i : i+1]
+	if strings.HasPrefix(l.src[i:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+
+	if strings.ContainsAny(prefix, "ft") {
+		lexFString(l, i+len(quote), quote)
+		return true
+	}
+
+	end := pythonStringEnd(l.src, i+len(quote), quote)
+	kind := stringToken
+	if !strings.Contains(prefix, "b") {
+		kind = pythonStatementKind(l, start, end)
+	}
+	l.emit(kind, end)
+	return true
+}
+
+func pythonStringEnd(src string, i int, quote string) int {
+	for i < len(src) {
+		switch {
+		case src[i] == '\\':
+			i += 2
+		case strings.HasPrefix(src[i:], quote):
+			return i + len(quote)
+		case src[i] == '\n' && len(quote) == 1:
+			return i
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+func lexFString(l *lexer, i int, quote string) {
+	for i < len(l.src) {
+		switch {
+		case l.src[i] == '\\':
+			i += 2
+		case strings.HasPrefix(l.src[i:], quote):
+			l.emit(stringToken, i+len(quote))
+			return
+		case l.src[i] == '\n' && len(quote) == 1:
+			l.emit(stringToken, i)
+			return
+		case strings.HasPrefix(l.src[i:], "{{") || strings.HasPrefix(l.src[i:], "}}"):
+			i += 2
+		case l.src[i] == '{':
+			l.emit(stringToken, i+1)
+			lexFStringField(l)
+			i = l.pos + 1
+		default:
+			i++
+		}
+	}
+	l.emit(stringToken, len(l.src))
+}
+
+func lexFStringField(l *lexer) {
+	depth := 0
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			l.pos++
+		case c == ')' || c == ']':
+			depth--
+			l.pos++
+		case c == '}':
+			if depth == 0 {
+				return
+			}
+			depth--
+			l.pos++
+		case (c == ':' || c == '!') && depth == 0 && !l.hasPrefix(string(c)+"="):
+			l.pos++
+			for l.pos < len(l.src) && l.src[l.pos] != '}' {
+				if l.src[l.pos] == '{' {
+					l.pos++
+					lexFStringField(l)
+					if l.pos >= len(l.src) {
+						return
+					}
+				}
+				l.pos++
+			}
+			return
+		default:
+			l.step()
+		}
+	}
+}
+
+func pythonStatementKind(l *lexer, start, end int) tokenKind {
+	if l.brackets > 0 || !pythonStatementEnds(l.src, end) {
+		return stringToken
+	}
+
+	prev := l.prevCode(start)
+	switch {
+	case prev == -1:
+		return docstringToken
+	case l.src[prev] == ':' && !(l.onlySpaceBefore(start) && pythonBlockContinues(l.src, start-l.lineStart(start), end)):
+		return stringToken
+	case l.src[prev] == ':' && pythonDefinitionHeader(l.src, prev):
+		return docstringToken
+	case l.onlySpaceBefore(start) && l.src[prev] != '\\':
+		return bareStringToken
+	}
+	return stringToken
+}
+
+func pythonBlockContinues(src string, indent, end int) bool {
+	lineEnd := strings.IndexByte(src[end:], '\n')
+	if lineEnd == -1 {
+		return false
+	}
+
+	for pos := end + lineEnd + 1; pos < len(src); {
+		line := src[pos:]
+		if next := strings.IndexByte(line, '\n'); next != -1 {
+			line = line[:next]
+		}
+		pos += len(line) + 1
+
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.TrimSpace(trimmed) == "" || trimmed[0] == '#' {
+			continue
+		}
+		return len(line)-len(trimmed) >= indent
+	}
+	return false
+}
+
+func pythonStatementEnds(src string, end int) bool {
+	for i := end; i < len(src); i++ {
+		switch src[i] {
+		case ' ', '\t':
+			continue
+		case '\r', '\n', '#', ';':
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func pythonDefinitionHeader(src string, colon int) bool {
+	depth := 0
+	i := colon - 1
+	for ; i >= 0; i-- {
+		switch src[i] {
+		case ')', ']', '}':
+			depth++
+		case '(', '[', '{':
+			depth--
+		case '\n':
+			if depth <= 0 && (i == 0 || src[i-1] != '\\') {
+				return isDefinition(strings.TrimSpace(src[i+1 : colon]))
+			}
+		}
+	}
+	return isDefinition(strings.TrimSpace(src[:colon]))
+}
+
+func isDefinition(header string) bool {
+	return strings.HasPrefix(header, "def ") || strings.HasPrefix(header, "async def ") || strings.HasPrefix(header, "class ")
+}
@@ -1,275 +1,256 @@
 package commentremover
 
 import (
-	"bytes"
-	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
 )
 
 func CommentRemover(code string, language string) string {
-	result := code
+	return RemoveComments(code, DefaultOptions(language))
+}
 
-	if language == "jsx" {
-		result = removeJSXComments(result)
-	}
+func RemoveComments(code string, opts Options) string {
+	result, _ := Remove(code, opts)
+	return result.Code
+}
 
-	switch language {
-	case "go", "c", "java", "javascript", "js", "jsx":
-		return removeCStyleComments(result)
-	case "python":
-		return removePythonComments(result)
-	default:
-		return removeCStyleComments(result)
+func Remove(code string, opts Options) (Result, error) {
+	language := languages.Canonical(opts.Language)
+	tokens := tokenize(code, language)
+	err := checkTerminated(code, language, tokens)
+	lexer := lexerFor(language)
+	if opts.StripDeadCode && lexer == "c" {
+		tokens = mergeTokens(tokens, cDeadBlocks(code, tokens))
+	}
+	markDocComments(code, tokens, syntaxFor(language).docComments)
+	if lexer == "go" {
+		markGoDocComments(code, tokens)
 	}
-}
 
-func removeJSXComments(code string) string {
-	var buffer bytes.Buffer
-	inString := false
-	stringChar := byte(0)
-	i := 0
-
-	for i < len(code) {
-		if (code[i] == '"' || code[i] == '\'' || code[i] == '`') && (i == 0 || code[i-1] != '\\') {
-			if !inString {
-				inString = true
-				stringChar = code[i]
-			} else if code[i] == stringChar {
-				inString = false
-			}
-			buffer.WriteByte(code[i])
-			i++
-			continue
+	rules := slices.Clone(opts.Preserve)
+	if opts.KeepDirectives {
+		for _, prefix := range opts.DirectivePrefixes {
+			rules = append(rules, PreserveRule{Prefix: prefix})
 		}
+	}
+	markPreserved(code, tokens, rules)
 
-		if !inString && i+2 < len(code) && code[i] == '{' && code[i+1] == '/' && code[i+2] == '*' {
-			commentEnd := strings.Index(code[i:], "*/}")
-			if commentEnd != -1 {
-				i += commentEnd + 3
-			} else {
-				buffer.WriteByte(code[i])
-				i++
-			}
-		} else {
-			buffer.WriteByte(code[i])
-			i++
+	var removed []token
+	remove := opts.remover(code)
+	for _, t := range tokens {
+		if remove(t) && (len(removed) == 0 || t.start >= removed[len(removed)-1].end) {
+			removed = append(removed, t)
 		}
 	}
 
-	result := buffer.String()
-
-	emptyLinePattern := regexp.MustCompile(`(?m)^\s*\n`)
-	result = emptyLinePattern.ReplaceAllString(result, "")
+	var out *output
+	if opts.KeepLineNumbers {
+		out = blankComments(code, removed)
+	} else {
+		out = stripComments(code, removed, opts.Whitespace)
+	}
 
-	return result
+	return Result{
+		Code:    out.String(),
+		Removed: removedSpans(code, removed),
+		origin:  out.origin,
+		input:   len(code),
+	}, err
 }
 
-func removeCStyleComments(code string) string {
-	var resultLines []string
-	lines := strings.Split(code, "\n")
+func tokenize(code string, language string) []token {
+	if lexerFor(language) == "go" {
+		if tokens, ok := lexGo(code); ok {
+			return tokens
+		}
+	}
 
-	inMultiLineComment := false
+	return lex(code, syntaxFor(language))
+}
 
-	for i := range lines {
-		line := lines[i]
-		trimmedLine := strings.TrimSpace(line)
+const probeSuffix = "\n\x00"
 
-		if len(trimmedLine) == 0 {
-			resultLines = append(resultLines, line)
+func checkTerminated(code string, language string, tokens []token) error {
+	for _, t := range tokens {
+		if t.end < len(code) || t.kind == lineCommentToken || t.openEnded {
 			continue
 		}
 
-		processedLine, stillInComment := processCStyleLine(line, inMultiLineComment)
-		inMultiLineComment = stillInComment
-
-		if len(strings.TrimSpace(processedLine)) == 0 {
-			continue
+		for _, probe := range tokenize(code+probeSuffix, language) {
+			if probe.start == t.start && probe.end > len(code)+1 {
+				return &UnterminatedError{Construct: t.kind.construct(), Start: positionOf(code, t.start)}
+			}
 		}
+		return nil
+	}
+	return nil
+}
 
-		resultLines = append(resultLines, processedLine)
+func mergeTokens(tokens []token, spans []token) []token {
+	if len(spans) == 0 {
+		return tokens
 	}
 
-	for len(resultLines) > 0 && strings.TrimSpace(resultLines[len(resultLines)-1]) == "" {
-		resultLines = resultLines[:len(resultLines)-1]
+	merged := slices.Clone(spans)
+	for _, t := range tokens {
+		covered := slices.ContainsFunc(spans, func(s token) bool {
+			return t.start < s.end && s.start < t.end
+		})
+		if !covered {
+			merged = append(merged, t)
+		}
 	}
 
-	return strings.Join(resultLines, "\n")
+	slices.SortFunc(merged, func(a, b token) int {
+		return a.start - b.start
+	})
+	return merged
 }
 
-func processCStyleLine(line string, startInComment bool) (string, bool) {
-	var result bytes.Buffer
-	inString := false
-	stringChar := byte(0)
-	inComment := startInComment
-	i := 0
-
-	for i < len(line) {
-		if inComment {
-			if i+1 < len(line) && line[i] == '*' && line[i+1] == '/' {
-				inComment = false
-				i += 2
-			} else {
-				i++
+func stripComments(code string, removed []token, whitespace WhitespacePolicy) *output {
+	stripped := &output{}
+	touched := make([]bool, strings.Count(code, "\n")+1)
+	line, prev := 0, 0
+
+	for _, t := range removed {
+		end := t.end
+		trim := false
+		rest := strings.TrimLeft(code[t.end:], " \t")
+		lineStart := strings.LastIndexByte(code[:t.start], '\n') + 1
+		switch {
+		case rest == "" || rest[0] == '\n' || rest[0] == '\r':
+			trim = true
+			end = len(code) - len(rest)
+		case strings.Contains(code[t.start:t.end], "\n"):
+			trim = true
+			if strings.TrimSpace(code[lineStart:t.start]) == "" {
+				end = len(code) - len(rest)
 			}
-			continue
+		case strings.TrimSpace(code[lineStart:t.start]) == "" || strings.TrimRight(code[prev:t.start], " \t") != code[prev:t.start]:
+			end = len(code) - len(rest)
 		}
 
-		if inString {
-			result.WriteByte(line[i])
-			if line[i] == '\\' && i+1 < len(line) {
-				if i+1 < len(line) {
-					result.WriteByte(line[i+1])
-				}
-				i += 2
-			} else if line[i] == stringChar {
-				inString = false
-				i++
-			} else {
-				i++
-			}
-			continue
+		stripped.copy(code, prev, t.start)
+		if trim {
+			stripped.trimTrailingSpace()
+		} else if n := len(stripped.buffer); t.kind != jsxCommentToken && n > 0 && !isSpace(stripped.buffer[n-1]) && end < len(code) && !isSpace(code[end]) {
+			stripped.insert(" ", t.start)
 		}
-
-		if line[i] == '"' || line[i] == '\'' || line[i] == '`' {
-			inString = true
-			stringChar = line[i]
-			result.WriteByte(line[i])
-			i++
-			continue
+		line += strings.Count(code[prev:t.start], "\n")
+		touched[line] = true
+
+		for i := t.start; i < t.end; i++ {
+			if code[i] == '\n' {
+				stripped.insert("\n", i)
+				line++
+				touched[line] = true
+			}
 		}
 
-		if i+1 < len(line) && line[i] == '/' && line[i+1] == '/' {
+		prev = end
+	}
+	stripped.copy(code, prev, len(code))
+
+	text := stripped.String()
+	var lines [][2]int
+	for start := 0; ; {
+		idx := strings.IndexByte(text[start:], '\n')
+		if idx == -1 {
+			lines = append(lines, [2]int{start, len(text)})
 			break
 		}
-
-		if i+1 < len(line) && line[i] == '/' && line[i+1] == '*' {
-			inComment = true
-			i += 2
-			continue
-		}
-
-		result.WriteByte(line[i])
-		i++
+		lines = append(lines, [2]int{start, start + idx})
+		start += idx + 1
 	}
 
-	return result.String(), inComment
-}
-
-func removePythonComments(code string) string {
-	var resultLines []string
-	lines := strings.Split(code, "\n")
-
-	inTripleQuote := false
-	tripleQuoteType := ""
+	finalNewline := len(lines) > 1 && lines[len(lines)-1][0] == len(text)
+	if finalNewline {
+		lines = lines[:len(lines)-1]
+	}
 
-	for i := range lines {
-		line := lines[i]
-		trimmedLine := strings.TrimSpace(line)
+	blank := func(l [2]int) bool {
+		return strings.TrimSpace(text[l[0]:l[1]]) == ""
+	}
 
-		if len(trimmedLine) == 0 {
-			resultLines = append(resultLines, line)
+	var kept [][2]int
+	for i, l := range lines {
+		switch {
+		case blank(l) && touched[i]:
 			continue
-		}
-
-		processedLine, stillInTripleQuote, quoteType := processPythonLine(line, inTripleQuote, tripleQuoteType)
-		inTripleQuote = stillInTripleQuote
-		tripleQuoteType = quoteType
-
-		if len(strings.TrimSpace(processedLine)) == 0 {
+		case blank(l) && whitespace == RemoveBlankLines:
+			continue
+		case blank(l) && whitespace == CollapseBlankLines && len(kept) > 0 && blank(kept[len(kept)-1]):
 			continue
 		}
-
-		resultLines = append(resultLines, processedLine)
-	}
-
-	for len(resultLines) > 0 && strings.TrimSpace(resultLines[len(resultLines)-1]) == "" {
-		resultLines = resultLines[:len(resultLines)-1]
+		kept = append(kept, l)
 	}
 
-	return strings.Join(resultLines, "\n")
-}
-
-func processPythonLine(line string, inTripleQuote bool, tripleQuoteType string) (string, bool, string) {
-	if inTripleQuote {
-		if strings.Contains(line, tripleQuoteType) {
-			idx := strings.Index(line, tripleQuoteType) + 3
-			if idx < len(line) {
-				return processSinglePythonLine(line[idx:]), false, ""
-			}
-			return "", false, ""
+	original := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	if !isBlankLine(original, 0) {
+		for len(kept) > 0 && blank(kept[0]) {
+			kept = kept[1:]
 		}
-		return "", true, tripleQuoteType
 	}
-
-	if strings.Contains(line, "'''") || strings.Contains(line, "\"\"\"") {
-		var quotePos int
-		var quoteType string
-
-		if pos := strings.Index(line, "'''"); pos != -1 {
-			quoteType = "'''"
-			quotePos = pos
-		} else {
-			quoteType = "\"\"\""
-			quotePos = strings.Index(line, "\"\"\"")
+	if !isBlankLine(original, len(original)-1) {
+		for len(kept) > 0 && blank(kept[len(kept)-1]) {
+			kept = kept[:len(kept)-1]
 		}
+	}
 
-		beforeQuote := processSinglePythonLine(line[:quotePos])
-
-		endPos := strings.Index(line[quotePos+3:], quoteType)
-		if endPos != -1 {
-			endPos += quotePos + 3 + 3
-
-			if endPos < len(line) {
-				afterQuote := processSinglePythonLine(line[endPos:])
-				return beforeQuote + afterQuote, false, ""
-			}
-			return beforeQuote, false, ""
+	result := &output{}
+	for i, l := range kept {
+		if i > 0 {
+			result.insert("\n", stripped.origin[kept[i-1][1]])
 		}
-
-		return beforeQuote, true, quoteType
+		result.extend(stripped, l[0], l[1])
 	}
-
-	return processSinglePythonLine(line), false, ""
+	if finalNewline && len(kept) > 0 {
+		result.insert("\n", stripped.origin[kept[len(kept)-1][1]])
+	}
+	return result
 }
 
-func processSinglePythonLine(line string) string {
-	var result bytes.Buffer
-	inString := false
-	stringChar := byte(0)
-	i := 0
+func blankComments(code string, removed []token) *output {
+	out := &output{}
+	prev := 0
 
-	for i < len(line) {
-		if !inString && line[i] == '#' {
-			break
+	for _, t := range removed {
+		comment := code[t.start:t.end]
+		rest := strings.TrimLeft(code[t.end:], " \t")
+		endsLine := rest == "" || rest[0] == '\n' || rest[0] == '\r'
+
+		out.copy(code, prev, t.start)
+		if endsLine || strings.Contains(comment, "\n") {
+			out.trimTrailingSpace()
 		}
 
-		if inString {
-			result.WriteByte(line[i])
-			if line[i] == '\\' && i+1 < len(line) {
-				if i+1 < len(line) {
-					result.WriteByte(line[i+1])
-				}
-				i += 2
-			} else if line[i] == stringChar {
-				inString = false
-				i++
-			} else {
-				i++
+		pending := &output{}
+		for i, r := range comment {
+			switch r {
+			case '\n', '\r':
+				pending = &output{}
+				out.insert(string(r), t.start+i)
+			case '\t':
+				pending.insert("\t", t.start+i)
+			default:
+				pending.insert(" ", t.start+i)
 			}
-			continue
 		}
 
-		if line[i] == '"' || line[i] == '\'' {
-			inString = true
-			stringChar = line[i]
-			result.WriteByte(line[i])
-			i++
-			continue
+		prev = t.end
+		if endsLine {
+			prev = len(code) - len(rest)
+		} else {
+			out.extend(pending, 0, len(pending.origin))
 		}
-
-		result.WriteByte(line[i])
-		i++
 	}
+	out.copy(code, prev, len(code))
+
+	return out
+}
 
-	return result.String()
+func isBlankLine(lines []string, i int) bool {
+	return i >= 0 && i < len(lines) && strings.TrimSpace(lines[i]) == ""
 }
@@ -0,0 +1,356 @@
+package commentremover
+
+import "testing"
+
+func TestCommentRemover(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		input    string
+		want     string
+	}{
+		{
+			name:     "go line comments and strings",
+			language: "go",
+			input:    "package main\n\n// c\nfunc main() {\n\ts := \"// not\" // yes\n\t_ = '/'\n}\n",
+			want:     "package main\n\nfunc main() {\n\ts := \"// not\"\n\t_ = '/'\n}\n",
+		},
+		{
+			name:     "go raw string spanning lines",
+			language: "go",
+			input:    "r := `/* raw\n// x */` // c\n",
+			want:     "r := `/* raw\n// x */`\n",
+		},
+		{
+			name:     "go directives and cgo preamble",
+			language: "go",
+			input:    "//go:build linux\n\npackage main\n\n/*\n#include <stdio.h>\n*/\nimport \"C\"\n\n// Foo is exported.\n//export Foo\nfunc Foo() {}\n",
+			want:     "//go:build linux\n\npackage main\n\n/*\n#include <stdio.h>\n*/\nimport \"C\"\n\n//export Foo\nfunc Foo() {}\n",
+		},
+		{
+			name:     "comments between tokens without spaces",
+			language: "go",
+			input:    "func f() error { return/* none */nil }\nx := a/* b *//* c */+1\n",
+			want:     "func f() error { return nil }\nx := a +1\n",
+		},
+		{
+			name:     "c tokens separated only by a comment",
+			language: "c",
+			input:    "unsigned/* wide */int x;\n",
+			want:     "unsigned int x;\n",
+		},
+		{
+			name:     "sql tokens separated only by a comment",
+			language: "sql",
+			input:    "SELECT a/* c */FROM t;\n",
+			want:     "SELECT a FROM t;\n",
+		},
+		{
+			name:     "css values separated only by a comment",
+			language: "css",
+			input:    "a { margin:0/* c */auto; }\n",
+			want:     "a { margin:0 auto; }\n",
+		},
+		{
+			name:     "c include paths, digit separators and raw strings",
+			language: "c",
+			input:    "#include <a/*b*/.h> // inc\nint x = 1'000; /* c */\nconst char *s = R\"x(/* no */)x\";\n",
+			want:     "#include <a/*b*/.h>\nint x = 1'000;\nconst char *s = R\"x(/* no */)x\";\n",
+		},
+		{
+			name:     "python strings and shebang",
+			language: "python",
+			input:    "#!/usr/bin/env python\nx = \"# no\"  # yes\ny = '''\n# kept\n'''\n",
+			want:     "#!/usr/bin/env python\nx = \"# no\"\ny = '''\n# kept\n'''\n",
+		},
+		{
+			name:     "python docstring",
+			language: "python",
+			input:    "def f():\n    \"\"\"Doc.\"\"\"\n    return 1\n",
+			want:     "def f():\n    return 1\n",
+		},
+		{
+			name:     "python docstring as the only statement",
+			language: "python",
+			input:    "class MyError(Exception):\n    \"\"\"Doc.\"\"\"\n\n\ndef f(): \"\"\"doc\"\"\"\n",
+			want:     "class MyError(Exception):\n    \"\"\"Doc.\"\"\"\n\n\ndef f(): \"\"\"doc\"\"\"\n",
+		},
+		{
+			name:     "python docstring followed by a comment-only line",
+			language: "python",
+			input:    "class A:\n    def f(self):\n        \"\"\"Doc.\"\"\"\n        # c\n\n    def g(self):\n        \"\"\"G.\"\"\"\n        return 1\n",
+			want:     "class A:\n    def f(self):\n        \"\"\"Doc.\"\"\"\n\n    def g(self):\n        return 1\n",
+		},
+		{
+			name:     "javascript regex and template literals",
+			language: "javascript",
+			input:    "const re = /\\/\\/ not/g; // c\nconst t = `a ${b /* c */} // d`;\n",
+			want:     "const re = /\\/\\/ not/g;\nconst t = `a ${b } // d`;\n",
+		},
+		{
+			name:     "jsx comment container",
+			language: "jsx",
+			input:    "const a = <div>{/* c */}<b>x</b></div>;\n",
+			want:     "const a = <div><b>x</b></div>;\n",
+		},
+		{
+			name:     "typescript generics and triple-slash directives",
+			language: "typescript",
+			input:    "/// <reference path=\"a.d.ts\" />\n/// plain\nfunction id<T>(x: T): T { return x } // c\nconst f = <T extends X>(x: T) => x; // c\nlet y = <string>z; /* c */\nif (a < b && c > /* r */ d) {}\n",
+			want:     "/// <reference path=\"a.d.ts\" />\nfunction id<T>(x: T): T { return x }\nconst f = <T extends X>(x: T) => x;\nlet y = <string>z;\nif (a < b && c > d) {}\n",
+		},
+		{
+			name:     "tsx generics and jsx tags",
+			language: "tsx",
+			input:    "const f = <T,>(x: T) => x; // c\nconst g = <T extends unknown>(x: T) => x; // c\nconst e = <div title=\"// no\">{/* c */}<T>// text</T></div>; // c\n",
+			want:     "const f = <T,>(x: T) => x;\nconst g = <T extends unknown>(x: T) => x;\nconst e = <div title=\"// no\"><T>// text</T></div>;\n",
+		},
+		{
+			name:     "tsx element with type arguments",
+			language: "tsx",
+			input:    "const el = <Foo<Bar> prop={1 /* c */} />; // c\nlet x = 1; // c\n",
+			want:     "const el = <Foo<Bar> prop={1 } />;\nlet x = 1;\n",
+		},
+		{
+			name:     "rust nested comments, raw strings and lifetimes",
+			language: "rust",
+			input:    "/* a /* b */ c */\nlet s = r#\"// no\"#; let l: &'a str = \"x\";\n",
+			want:     "let s = r#\"// no\"#; let l: &'a str = \"x\";\n",
+		},
+		{
+			name:     "java text block",
+			language: "java",
+			input:    "String s = \"\"\"\n  // kept\n  \"\"\"; // gone\n",
+			want:     "String s = \"\"\"\n  // kept\n  \"\"\";\n",
+		},
+		{
+			name:     "kotlin string template",
+			language: "kotlin",
+			input:    "val s = \"${a /* c */ + 1} // no\" // yes\n",
+			want:     "val s = \"${a + 1} // no\"\n",
+		},
+		{
+			name:     "bash heredoc",
+			language: "bash",
+			input:    "echo \"# no\" # yes\ncat <<EOF\n# kept\nEOF\n",
+			want:     "echo \"# no\"\ncat <<EOF\n# kept\nEOF\n",
+		},
+		{
+			name:     "ruby block comment and interpolation",
+			language: "ruby",
+			input:    "x = 1 # c\n=begin\nblock\n=end\ny = \"#{x} # no\"\n",
+			want:     "x = 1\ny = \"#{x} # no\"\n",
+		},
+		{
+			name:     "yaml",
+			language: "yaml",
+			input:    "key: \"# no\" # yes\nurl: http://x#y\n",
+			want:     "key: \"# no\"\nurl: http://x#y\n",
+		},
+		{
+			name:     "sql",
+			language: "sql",
+			input:    "SELECT '--no' -- yes\nFROM t /* c */;\n",
+			want:     "SELECT '--no'\nFROM t ;\n",
+		},
+		{
+			name:     "mysql hash comments and the dash-space rule",
+			language: "mysql",
+			input:    "SELECT 1 # c\nSELECT 2 -- c\nSELECT 3--1 AS x;\nSELECT '#no', `a``#`, 'it\\'s # no' /* c */;\n/*! 40101 hint */\n--\n",
+			want:     "SELECT 1\nSELECT 2\nSELECT 3--1 AS x;\nSELECT '#no', `a``#`, 'it\\'s # no' ;\n/*! 40101 hint */\n",
+		},
+		{
+			name:     "postgresql dollar-quoted and escape strings, nested comments",
+			language: "postgresql",
+			input:    "SELECT $$ -- no $$, $tag$ /* no */ $tag$, E'\\' -- no' -- yes\n/* a /* b */ c */ SELECT 1;\nSELECT $1 -- c\n",
+			want:     "SELECT $$ -- no $$, $tag$ /* no */ $tag$, E'\\' -- no'\nSELECT 1;\nSELECT $1\n",
+		},
+		{
+			name:     "lua long bracket levels",
+			language: "lua",
+			input:    "--[==[ a\n]] still ]==]\nx = [=[ --[[ no ]=] -- c\n--- doc\n",
+			want:     "x = [=[ --[[ no ]=]\n",
+		},
+		{
+			name:     "lua long strings and comments",
+			language: "lua",
+			input:    "local s = \"--no\" -- yes\n--[[ block\n]]\nx = [[ --no ]]\n",
+			want:     "local s = \"--no\"\nx = [[ --no ]]\n",
+		},
+		{
+			name:     "haskell nested comments",
+			language: "haskell",
+			input:    "{- a {- b -} c -}\nmain = putStrLn \"--no\" -- yes\n",
+			want:     "main = putStrLn \"--no\"\n",
+		},
+		{
+			name:     "html attributes and scripts",
+			language: "html",
+			input:    "<!-- c -->\n<p title=\"<!-- no -->\">x</p>\n<script>// js\nlet a = 1;</script>\n",
+			want:     "<p title=\"<!-- no -->\">x</p>\n<script>\nlet a = 1;</script>\n",
+		},
+		{
+			name:     "vue typescript script and scoped style",
+			language: "vue",
+			input:    "<template>\n  <!-- c -->\n  <p>{{ a }}</p>\n</template>\n<script lang=\"ts\">\nconst f = <T,>(x: T) => x; // c\n</script>\n<style scoped>\n/* s */ p { color: red; }\n</style>\n",
+			want:     "<template>\n  <p>{{ a }}</p>\n</template>\n<script lang=\"ts\">\nconst f = <T,>(x: T) => x;\n</script>\n<style scoped>\np { color: red; }\n</style>\n",
+		},
+		{
+			name:     "svelte script and scss style",
+			language: "svelte",
+			input:    "<script>\n// c\nlet a = '<!-- no -->';\n</script>\n<!-- c -->\n<p>{a}</p>\n<style lang=\"scss\">\n// s\n$x: 1;\n</style>\n",
+			want:     "<script>\nlet a = '<!-- no -->';\n</script>\n<p>{a}</p>\n<style lang=\"scss\">\n$x: 1;\n</style>\n",
+		},
+		{
+			name:     "xml cdata and attributes",
+			language: "xml",
+			input:    "<?xml version=\"1.0\"?>\n<!-- c -->\n<a b='<!-- no -->'><![CDATA[<!-- x -->]]></a>\n",
+			want:     "<?xml version=\"1.0\"?>\n<a b='<!-- no -->'><![CDATA[<!-- x -->]]></a>\n",
+		},
+		{
+			name:     "html conditional comments",
+			language: "html",
+			input:    "<!--[if IE]><p>old</p><![endif]-->\n<!-- c -->\n<p>don't</p>\n",
+			want:     "<!--[if IE]><p>old</p><![endif]-->\n<p>don't</p>\n",
+		},
+		{
+			name:     "css",
+			language: "css",
+			input:    "a { color: red; /* c */ content: \"/* no */\"; }\n",
+			want:     "a { color: red; content: \"/* no */\"; }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommentRemover(tt.input, tt.language); got != tt.want {
+				t.Errorf("CommentRemover(%q, %q)\n got %q\nwant %q", tt.input, tt.language, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreserveRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		rules    []string
+		input    string
+		want     string
+	}{
+		{
+			name:     "license header kept by default",
+			language: "c",
+			input:    "/*! MIT */\n// SPDX-License-Identifier: MIT\n// c\nint x;\n",
+			want:     "/*! MIT */\n// SPDX-License-Identifier: MIT\nint x;\n",
+		},
+		{
+			name:     "lint pragmas",
+			language: "python",
+			rules:    []string{"noqa"},
+			input:    "import os  # noqa: F401\nx = 1  # c\n",
+			want:     "import os  # noqa: F401\nx = 1\n",
+		},
+		{
+			name:     "regexp rule",
+			language: "javascript",
+			rules:    []string{"re:keep"},
+			input:    "a(); // keep me\nb(); // drop me\n",
+			want:     "a(); // keep me\nb();\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions(tt.language)
+			if tt.rules != nil {
+				rules, err := ParsePreserveRules(tt.rules)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts.Preserve = rules
+			}
+			if got := RemoveComments(tt.input, opts); got != tt.want {
+				t.Errorf("RemoveComments(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhitespace(t *testing.T) {
+	const input = "// header\n\npackage main\n\n\n\nfunc a() {} // a\n\n// between\n\nfunc b() {}"
+
+	tests := []struct {
+		name     string
+		language string
+		policy   WhitespacePolicy
+		input    string
+		want     string
+	}{
+		{name: "preserve blank lines", language: "go", policy: PreserveBlankLines, input: input, want: "package main\n\n\n\nfunc a() {}\n\n\nfunc b() {}"},
+		{name: "collapse blank lines", language: "go", policy: CollapseBlankLines, input: input, want: "package main\n\nfunc a() {}\n\nfunc b() {}"},
+		{name: "remove blank lines", language: "go", policy: RemoveBlankLines, input: input, want: "package main\nfunc a() {}\nfunc b() {}"},
+		{name: "original leading and trailing blank lines", language: "go", input: "\n\nx := 1 // c\n\n", want: "\n\nx := 1\n\n"},
+		{name: "adjacent trailing comments", language: "go", input: "x := 1 /* a */ // b\n", want: "x := 1\n"},
+		{name: "multi-line comment before code", language: "go", input: "x := 1\n/* a\n b */ y := 2\n", want: "x := 1\ny := 2\n"},
+		{name: "adjacent block comments on their own line", language: "c", input: "/* a */ /* b */\nint x;\n", want: "int x;\n"},
+		{name: "adjacent trailing block comments", language: "kotlin", input: "val x = 1 /* a */ /* b */\nval y = 2\n", want: "val x = 1\nval y = 2\n"},
+		{name: "adjacent trailing comments in rust", language: "rust", input: "let x = 1; /* a */ // b\n", want: "let x = 1;\n"},
+		{name: "adjacent trailing comments in lua", language: "lua", input: "x = 1 --[[ a ]] -- b\n", want: "x = 1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions(tt.language)
+			opts.Whitespace = tt.policy
+			if got := RemoveComments(tt.input, opts); got != tt.want {
+				t.Errorf("RemoveComments(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepLineNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "comments become spaces", input: "x := 1 // c\n/* a\n b */ y := 2\n", want: "x := 1\n\n      y := 2\n"},
+		{name: "adjacent trailing comments", input: "x := 1 /* a */ // b\ny := 2\n", want: "x := 1\ny := 2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions("go")
+			opts.KeepLineNumbers = true
+			if got := RemoveComments(tt.input, opts); got != tt.want {
+				t.Errorf("RemoveComments(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripDeadCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "if 0 block", input: "#if 0\nold(); // c\n#endif\nint x; // c\n", want: "int x;\n"},
+		{name: "nested if", input: "#if 0\n#if X\na();\n#endif\n#endif\nint x;\n", want: "int x;\n"},
+		{name: "else branch kept", input: "#if 0\na();\n#else\nkept();\n#endif\n", want: "kept();\n"},
+		{name: "elif bails out", input: "#if 0\nb();\n#elif Y\nc();\n#endif\n", want: "#if 0\nb();\n#elif Y\nc();\n#endif\n"},
+		{name: "if 1 untouched", input: "#if 1\na();\n#endif\n", want: "#if 1\na();\n#endif\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions("c")
+			opts.StripDeadCode = true
+			if got := RemoveComments(tt.input, opts); got != tt.want {
+				t.Errorf("RemoveComments(%q)\n got %q\nwant %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package commentremover
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type CommentKind int
+
+const (
+	LineComment CommentKind = iota
+	BlockComment
+	DocComment
+	JSXComment
+	Docstring
+	BareString
+	DeadCode
+)
+
+var commentKindNames = map[CommentKind]string{
+	LineComment:  "line",
+	BlockComment: "block",
+	DocComment:   "doc",
+	JSXComment:   "jsx",
+	Docstring:    "docstring",
+	BareString:   "bare-string",
+	DeadCode:     "dead-code",
+}
+
+func (k CommentKind) String() string {
+	return commentKindNames[k]
+}
+
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+type Span struct {
+	Kind  CommentKind
+	Start Position
+	End   Position
+	Text  string
+}
+
+type Result struct {
+	Code    string
+	Removed []Span
+	origin  []int
+	input   int
+}
+
+func (r Result) InputOffset(outputOffset int) int {
+	if outputOffset < 0 {
+		return 0
+	}
+	if outputOffset >= len(r.origin) {
+		return r.input
+	}
+	return r.origin[outputOffset]
+}
+
+func (k tokenKind) commentKind() CommentKind {
+	switch k {
+	case lineCommentToken:
+		return LineComment
+	case docCommentToken:
+		return DocComment
+	case jsxCommentToken:
+		return JSXComment
+	case docstringToken:
+		return Docstring
+	case bareStringToken:
+		return BareString
+	case deadCodeToken:
+		return DeadCode
+	default:
+		return BlockComment
+	}
+}
+
+func removedSpans(code string, removed []token) []Span {
+	spans := make([]Span, 0, len(removed))
+	line, lineStart, prev := 1, 0, 0
+
+	position := func(offset int) Position {
+		segment := code[prev:offset]
+		if n := strings.Count(segment, "\n"); n > 0 {
+			line += n
+			lineStart = prev + strings.LastIndexByte(segment, '\n') + 1
+		}
+		prev = offset
+		return Position{Offset: offset, Line: line, Column: utf8.RuneCountInString(code[lineStart:offset]) + 1}
+	}
+
+	for _, t := range removed {
+		spans = append(spans, Span{
+			Kind:  t.kind.commentKind(),
+			Start: position(t.start),
+			End:   position(t.end),
+			Text:  code[t.start:t.end],
+		})
+	}
+	return spans
+}
+
+func positionOf(code string, offset int) Position {
+	lineStart := strings.LastIndexByte(code[:offset], '\n') + 1
+	return Position{
+		Offset: offset,
+		Line:   strings.Count(code[:offset], "\n") + 1,
+		Column: utf8.RuneCountInString(code[lineStart:offset]) + 1,
+	}
+}
+
+type UnterminatedError struct {
+	Construct string
+	Start     Position
+}
+
+func (e *UnterminatedError) Error() string {
+	return fmt.Sprintf("unterminated %s starting at line %d, column %d", e.Construct, e.Start.Line, e.Start.Column)
+}
+
+func (k tokenKind) construct() string {
+	switch k {
+	case stringToken, bareStringToken:
+		return "string"
+	case docstringToken:
+		return "docstring"
+	case jsxCommentToken:
+		return "JSX comment"
+	default:
+		return "comment"
+	}
+}
+
+type output struct {
+	buffer []byte
+	origin []int
+}
+
+func (o *output) copy(code string, from, to int) {
+	o.buffer = append(o.buffer, code[from:to]...)
+	for i := from; i < to; i++ {
+		o.origin = append(o.origin, i)
+	}
+}
+
+func (o *output) insert(text string, at int) {
+	o.buffer = append(o.buffer, text...)
+	for range len(text) {
+		o.origin = append(o.origin, at)
+	}
+}
+
+func (o *output) extend(src *output, from, to int) {
+	o.buffer = append(o.buffer, src.buffer[from:to]...)
+	o.origin = append(o.origin, src.origin[from:to]...)
+}
+
+func (o *output) trimTrailingSpace() {
+	n := len(o.buffer)
+	for n > 0 && (o.buffer[n-1] == ' ' || o.buffer[n-1] == '\t') {
+		n--
+	}
+	o.buffer = o.buffer[:n]
+	o.origin = o.origin[:n]
+}
+
+func (o *output) String() string {
+	return string(o.buffer)
+}
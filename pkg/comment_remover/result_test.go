@@ -0,0 +1,95 @@
+package commentremover
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveSpans(t *testing.T) {
+	const input = "x := 1 // c\n/* é\n b */ y := 2\n"
+
+	result, err := Remove(input, DefaultOptions("go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "x := 1\ny := 2\n"; result.Code != want {
+		t.Fatalf("Code = %q, want %q", result.Code, want)
+	}
+
+	want := []Span{
+		{Kind: LineComment, Start: Position{Offset: 7, Line: 1, Column: 8}, End: Position{Offset: 11, Line: 1, Column: 12}, Text: "// c"},
+		{Kind: BlockComment, Start: Position{Offset: 12, Line: 2, Column: 1}, End: Position{Offset: 23, Line: 3, Column: 6}, Text: "/* é\n b */"},
+	}
+	if !reflect.DeepEqual(result.Removed, want) {
+		t.Errorf("Removed = %+v, want %+v", result.Removed, want)
+	}
+
+	for out, in := range map[int]int{0: 0, 5: 5, 6: 11, 7: 24, 13: 30, 100: len(input), -1: 0} {
+		if got := result.InputOffset(out); got != in {
+			t.Errorf("InputOffset(%d) = %d, want %d", out, got, in)
+		}
+	}
+}
+
+func TestRemoveSpanKinds(t *testing.T) {
+	tests := []struct {
+		language string
+		input    string
+		want     []CommentKind
+	}{
+		{language: "rust", input: "/// doc\n// line\n/* block */\n", want: []CommentKind{DocComment, LineComment, BlockComment}},
+		{language: "jsx", input: "<div>{/* c */}</div>\n", want: []CommentKind{JSXComment}},
+		{language: "python", input: "def f():\n    \"\"\"Doc.\"\"\"\n    return 1\n", want: []CommentKind{Docstring}},
+	}
+
+	for _, tt := range tests {
+		result, _ := Remove(tt.input, DefaultOptions(tt.language))
+		var got []CommentKind
+		for _, span := range result.Removed {
+			got = append(got, span.Kind)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: kinds = %v, want %v", tt.language, got, tt.want)
+		}
+	}
+}
+
+func TestUnterminated(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		input     string
+		construct string
+		line      int
+		column    int
+	}{
+		{name: "block comment", language: "c", input: "int x;\n  /* open", construct: "comment", line: 2, column: 3},
+		{name: "go raw string", language: "go", input: "s := `abc", construct: "string", line: 1, column: 6},
+		{name: "python triple-quoted string", language: "python", input: "def f():\n    \"\"\"Doc\n    return 1", construct: "string", line: 2, column: 5},
+		{name: "nested comment", language: "rust", input: "/* a /* b */", construct: "comment", line: 1, column: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Remove(tt.input, DefaultOptions(tt.language))
+			unterminated, ok := err.(*UnterminatedError)
+			if !ok {
+				t.Fatalf("Remove(%q) error = %v, want *UnterminatedError", tt.input, err)
+			}
+			if unterminated.Construct != tt.construct || unterminated.Start.Line != tt.line || unterminated.Start.Column != tt.column {
+				t.Errorf("got %s at %d:%d, want %s at %d:%d", unterminated.Construct, unterminated.Start.Line, unterminated.Start.Column, tt.construct, tt.line, tt.column)
+			}
+		})
+	}
+
+	for language, input := range map[string]string{
+		"go":     "x := 1 // trailing",
+		"c":      "/* closed */ int x;",
+		"ruby":   "x = 1\n__END__\ndata",
+		"python": "s = 'a'",
+	} {
+		if _, err := Remove(input, DefaultOptions(language)); err != nil {
+			t.Errorf("Remove(%q, %s) error = %v, want nil", input, language, err)
+		}
+	}
+}
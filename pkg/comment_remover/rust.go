@@ -0,0 +1,74 @@
+package commentremover
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+func scanRustLiteral(l *lexer) bool {
+	switch l.src[l.pos] {
+	case 'r', 'b', 'c':
+		return scanRustRawString(l)
+	case '\'':
+		return scanRustChar(l)
+	}
+	return false
+}
+
+func scanRustRawString(l *lexer) bool {
+	if l.pos > 0 && isIdentByte(l.src[l.pos-1]) {
+		return false
+	}
+
+	i := l.pos
+	if l.src[i] == 'b' || l.src[i] == 'c' {
+		i++
+	}
+	if i >= len(l.src) || l.src[i] != 'r' {
+		return false
+	}
+	i++
+
+	hashes := 0
+	for i < len(l.src) && l.src[i] == '#' {
+		hashes++
+		i++
+	}
+	if i >= len(l.src) || l.src[i] != '"' {
+		return false
+	}
+
+	closing := `"` + strings.Repeat("#", hashes)
+	idx := strings.Index(l.src[i+1:], closing)
+	if idx == -1 {
+		l.emit(stringToken, len(l.src))
+	} else {
+		l.emit(stringToken, i+1+idx+len(closing))
+	}
+	return true
+}
+
+func scanRustChar(l *lexer) bool {
+	i := l.pos + 1
+	if i >= len(l.src) {
+		return false
+	}
+
+	if l.src[i] == '\\' {
+		idx := strings.IndexByte(l.src[min(i+2, len(l.src)):], '\'')
+		if idx == -1 || strings.ContainsRune(l.src[i:i+2+idx], '\n') {
+			return false
+		}
+		l.emit(stringToken, i+2+idx+1)
+		return true
+	}
+
+	_, size := utf8.DecodeRuneInString(l.src[i:])
+	if i+size < len(l.src) && l.src[i+size] == '\'' && l.src[i] != '\n' {
+		l.emit(stringToken, i+size+1)
+		return true
+	}
+
+	l.pos++
+	return true
+}
@@ -0,0 +1,104 @@
+package commentremover
+
+import "github.com/Ross1116/coder-copy/pkg/languages"
+
+var (
+	doubleQuoted = stringRule{open: `"`, close: `"`, escape: '\\'}
+	singleQuoted = stringRule{open: "'", close: "'", escape: '\\'}
+	cBlock       = delimiter{open: "/*", close: "*/"}
+)
+
+type lexerHooks struct {
+	scan             func(l *lexer) bool
+	lineContinuation bool
+	ownComments      bool
+	ownStrings       bool
+}
+
+var lexers map[string]lexerHooks
+
+func init() {
+	lexers = map[string]lexerHooks{
+		"go":         {},
+		"c":          {scan: scanCPP, lineContinuation: true},
+		"java":       {},
+		"kotlin":     {scan: scanKotlin},
+		"scala":      {},
+		"swift":      {},
+		"rust":       {scan: scanRustLiteral},
+		"python":     {scan: scanPython, ownStrings: true},
+		"javascript": {scan: func(l *lexer) bool { return scanJavaScript(l, true, false) }},
+		"typescript": {scan: func(l *lexer) bool { return scanJavaScript(l, false, true) }},
+		"tsx":        {scan: func(l *lexer) bool { return scanJavaScript(l, true, true) }},
+		"bash":       {scan: scanShell, ownComments: true, ownStrings: true},
+		"ruby":       {scan: scanRuby, ownComments: true, ownStrings: true},
+		"perl":       {scan: scanPerl, ownComments: true, ownStrings: true},
+		"yaml":       {scan: scanYAML, ownComments: true, ownStrings: true},
+		"toml":       {},
+		"dockerfile": {scan: scanDockerfile, ownComments: true, ownStrings: true},
+		"makefile":   {scan: scanMakefile, ownComments: true, ownStrings: true},
+		"sql":        {},
+		"mysql":      {scan: scanMySQLComment},
+		"postgresql": {scan: scanPostgresString},
+		"lua":        {scan: scanLua, ownComments: true},
+		"haskell":    {scan: scanHaskell},
+		"html":       {scan: func(l *lexer) bool { return scanMarkup(l, true) }, ownComments: true, ownStrings: true},
+		"xml":        {scan: func(l *lexer) bool { return scanMarkup(l, false) }, ownComments: true, ownStrings: true},
+		"css":        {scan: scanCSSURL},
+		"scss":       {scan: scanCSSURL},
+		"less":       {scan: scanCSSURL},
+	}
+}
+
+func lexerFor(language string) string {
+	if lang, ok := languages.Lookup(language); ok {
+		return lang.Lexer
+	}
+	return ""
+}
+
+func syntaxFor(language string) *syntax {
+	lang, ok := languages.Lookup(language)
+	if !ok {
+		lang, _ = languages.Lookup("c")
+		return declaredSyntax(lang)
+	}
+
+	if base, ok := languages.Lookup(lang.Lexer); ok && len(lang.LineComments)+len(lang.BlockComments)+len(lang.Strings) == 0 {
+		lang = base
+	}
+
+	s := declaredSyntax(lang)
+	hooks, ok := lexers[lang.Lexer]
+	if !ok {
+		return s
+	}
+	s.scan = hooks.scan
+	s.lineContinuation = hooks.lineContinuation
+	if hooks.ownComments {
+		s.lineComments, s.blockComments = nil, nil
+	}
+	if hooks.ownStrings {
+		s.strings = nil
+	}
+	return s
+}
+
+func declaredSyntax(lang languages.Language) *syntax {
+	s := &syntax{
+		lineComments:   lang.LineComments,
+		nestedComments: lang.NestedComments,
+		docComments:    lang.DocComments,
+	}
+	for _, d := range lang.BlockComments {
+		s.blockComments = append(s.blockComments, delimiter{open: d.Open, close: d.Close})
+	}
+	for _, str := range lang.Strings {
+		rule := stringRule{open: str.Open, close: str.Close, doubled: str.Doubled, multiline: str.Multiline}
+		if str.Escape != "" {
+			rule.escape = str.Escape[0]
+		}
+		s.strings = append(s.strings, rule)
+	}
+	return s
+}
@@ -0,0 +1,58 @@
+package commentremover
+
+import (
+	"testing"
+
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
+func TestBuiltinLexers(t *testing.T) {
+	for _, lang := range languages.All() {
+		if !lang.Builtin {
+			continue
+		}
+		if _, ok := lexers[lang.Lexer]; !ok {
+			t.Errorf("%s: lexer %q is not defined", lang.Name, lang.Lexer)
+		}
+	}
+}
+
+func TestCustomLanguages(t *testing.T) {
+	for _, lang := range []languages.Language{
+		{
+			Name:           "nimtest",
+			LineComments:   []string{"#"},
+			BlockComments:  []languages.Delimiter{{Open: "#[", Close: "]#"}},
+			NestedComments: true,
+			Strings:        []languages.StringSyntax{{Open: `"`, Close: `"`, Escape: `\`}},
+		},
+		{Name: "ctest", Lexer: "c"},
+		{
+			Name:          "sqltest",
+			Lexer:         "sql",
+			LineComments:  []string{"//"},
+			BlockComments: []languages.Delimiter{{Open: "(*", Close: "*)"}},
+			Strings:       []languages.StringSyntax{{Open: "'", Close: "'", Doubled: true}},
+		},
+	} {
+		if err := languages.Register(lang); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		language string
+		input    string
+		want     string
+	}{
+		{language: "nimtest", input: "echo \"# no\" # yes\n#[ a #[ b ]# c ]#\nx = 1\n", want: "echo \"# no\"\nx = 1\n"},
+		{language: "sqltest", input: "SELECT '//no' // yes\nFROM t (* c *) -- kept\n", want: "SELECT '//no'\nFROM t -- kept\n"},
+		{language: "ctest", input: "int x = 1'000; // c\nconst char *s = R\"x(/* no */)x\";\n", want: "int x = 1'000;\nconst char *s = R\"x(/* no */)x\";\n"},
+	}
+
+	for _, tt := range tests {
+		if got := CommentRemover(tt.input, tt.language); got != tt.want {
+			t.Errorf("CommentRemover(%q, %s)\n got %q\nwant %q", tt.input, tt.language, got, tt.want)
+		}
+	}
+}
@@ -2,12 +2,38 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
+
+	commentremover "github.com/Ross1116/coder-copy/pkg/comment_remover"
+	"github.com/Ross1116/coder-copy/pkg/languages"
 )
 
 type Config struct {
-	Language string
-	Format   bool
+	Language         string
+	Format           bool
+	Preserve         []string
+	LanguagePreserve map[string][]string
+	DropBareStrings  bool
+	StripDeadCode    bool
+	Policy           string
+	PolicyPattern    string
+	BlankLines       string
+	KeepLineNumbers  bool
+	RefuseTruncated  bool
+	MinConfidence    float64
+	Markdown         bool
+	Extract          bool
+	LineNumbers      bool
+	Declarations     bool
+	ConvertTo        string
+}
+
+type Result struct {
+	Content string
+	Notes   []string
 }
 
 func GetConfig() *Config {
@@ -19,32 +45,92 @@ func GetConfig() *Config {
 }
 
 func parseFlags() *Config {
-	goPtr := flag.Bool("go", false, "Remove Go style comments")
-	cLangPtr := flag.Bool("c", false, "Remove C/C++ style comments")
-	javaPtr := flag.Bool("java", false, "Remove Java style comments")
-	pythonPtr := flag.Bool("python", false, "Remove Python style comments")
-	jsPtr := flag.Bool("js", false, "Remove JavaScript style comments")
-	jsxPtr := flag.Bool("jsx", false, "Remove JSX style comments")
 	formatPtr := flag.Bool("format", false, "Format the copied code automatically")
+	dropBareStringsPtr := flag.Bool("drop-bare-strings", false, "Also remove Python string statements that are not docstrings")
+	stripDeadCodePtr := flag.Bool("strip-if0", false, "Also remove C/C++ #if 0 ... #endif blocks")
+	policyPtr := flag.String("policy", "all", "Which comments to remove: all, keep-docs, inline or matching")
+	keepDocsPtr := flag.Bool("keep-docs", false, "Keep documentation comments (same as -policy keep-docs)")
+	policyPatternPtr := flag.String("policy-pattern", "", "Regexp selecting comments to remove with -policy matching (default TODO|FIXME|HACK|XXX)")
+	blankLinesPtr := flag.String("blank-lines", "preserve", "How to treat blank lines: preserve, collapse or remove")
+	keepLinesPtr := flag.Bool("keep-lines", false, "Blank out comments so remaining code keeps its line and column")
+	refuseTruncatedPtr := flag.Bool("refuse-truncated", false, "Leave the clipboard untouched when the code ends inside an unterminated comment or string")
+	autoPtr := flag.Bool("auto", false, "Detect the language of every clipboard item")
+	minConfidencePtr := flag.Float64("min-confidence", commentremover.DefaultMinConfidence, "Leave the clipboard untouched when -auto is less confident than this (0-1)")
+	markdownPtr := flag.Bool("markdown", false, "Only clean fenced and indented code blocks, leaving the surrounding Markdown untouched")
+	extractPtr := flag.Bool("extract", false, "Copy only the comments instead of the code")
+	lineNumbersPtr := flag.Bool("line-numbers", false, "Prefix extracted comments with their line numbers")
+	declarationsPtr := flag.Bool("declarations", false, "Follow each extracted comment with the line it annotates")
+	convertToPtr := flag.String("convert-to", "", "Convert comments to another language's comment style instead of removing them (e.g. go)")
+	preservePtr := flag.String("preserve", "", "Comma-separated comment preserve rules, optionally per language (e.g. shebang,license,re:^// keep or python=noqa,shebang;js=eslint)")
+
+	type languageFlag struct {
+		name    string
+		enabled *bool
+	}
+	var languageFlags []languageFlag
+	for _, lang := range languages.All() {
+		if flag.Lookup(lang.Flag) != nil {
+			continue
+		}
+		languageFlags = append(languageFlags, languageFlag{
+			name:    lang.Name,
+			enabled: flag.Bool(lang.Flag, false, fmt.Sprintf("Remove %s comments", lang.Title)),
+		})
+	}
 	flag.Parse()
 
 	language := "go"
-	if *cLangPtr {
-		language = "c"
-	} else if *javaPtr {
-		language = "java"
-	} else if *pythonPtr {
-		language = "python"
-	} else if *jsPtr {
-		language = "javascript"
-	} else if *jsxPtr {
-		language = "jsx"
-	} else if *goPtr {
-		language = "go"
+	for _, f := range languageFlags {
+		if *f.enabled && language == "go" {
+			language = f.name
+		}
+	}
+	if *autoPtr {
+		language = languages.Auto
 	}
 
+	policy := *policyPtr
+	if *keepDocsPtr {
+		policy = "keep-docs"
+	}
+
+	preserve, languagePreserve := parsePreserve(*preservePtr)
+
 	return &Config{
-		Language: language,
-		Format:   *formatPtr,
+		Language:         language,
+		Format:           *formatPtr,
+		Preserve:         preserve,
+		LanguagePreserve: languagePreserve,
+		DropBareStrings:  *dropBareStringsPtr,
+		StripDeadCode:    *stripDeadCodePtr,
+		Policy:           policy,
+		PolicyPattern:    *policyPatternPtr,
+		BlankLines:       *blankLinesPtr,
+		KeepLineNumbers:  *keepLinesPtr,
+		RefuseTruncated:  *refuseTruncatedPtr,
+		MinConfidence:    *minConfidencePtr,
+		Markdown:         *markdownPtr,
+		Extract:          *extractPtr,
+		LineNumbers:      *lineNumbersPtr,
+		Declarations:     *declarationsPtr,
+		ConvertTo:        *convertToPtr,
+	}
+}
+
+var languagePreservePattern = regexp.MustCompile(`^\s*([\w+#.-]+)=(.*)$`)
+
+func parsePreserve(spec string) ([]string, map[string][]string) {
+	var preserve []string
+	var languagePreserve map[string][]string
+	for _, part := range strings.Split(spec, ";") {
+		if m := languagePreservePattern.FindStringSubmatch(part); m != nil {
+			if languagePreserve == nil {
+				languagePreserve = map[string][]string{}
+			}
+			languagePreserve[languages.Canonical(m[1])] = strings.Split(m[2], ",")
+		} else if strings.TrimSpace(part) != "" {
+			preserve = append(preserve, strings.Split(part, ",")...)
+		}
 	}
+	return preserve, languagePreserve
 }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePreserve(t *testing.T) {
+	tests := []struct {
+		spec             string
+		preserve         []string
+		languagePreserve map[string][]string
+	}{
+		{spec: ""},
+		{spec: "shebang,re:^// keep=1", preserve: []string{"shebang", "re:^// keep=1"}},
+		{
+			spec:             "python=noqa,shebang;js=eslint",
+			languagePreserve: map[string][]string{"python": {"noqa", "shebang"}, "javascript": {"eslint"}},
+		},
+		{
+			spec:             "license;go=",
+			preserve:         []string{"license"},
+			languagePreserve: map[string][]string{"go": {""}},
+		},
+	}
+
+	for _, tt := range tests {
+		preserve, languagePreserve := parsePreserve(tt.spec)
+		if !reflect.DeepEqual(preserve, tt.preserve) || !reflect.DeepEqual(languagePreserve, tt.languagePreserve) {
+			t.Errorf("parsePreserve(%q) = %q, %q; want %q, %q", tt.spec, preserve, languagePreserve, tt.preserve, tt.languagePreserve)
+		}
+	}
+}
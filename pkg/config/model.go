@@ -1,54 +1,113 @@
 package config
 
+import (
+	commentremover "github.com/Ross1116/coder-copy/pkg/comment_remover"
+	"github.com/Ross1116/coder-copy/pkg/languages"
+)
+
 type screenState int
 
 const (
 	languageSelect screenState = iota
+	policySelect
+	styleSelect
 	formatSelect
 	monitoring
 	contentView
 )
 
+type choice struct {
+	name  string
+	value string
+}
+
 type Model struct {
 	screen          screenState
 	cursor          int
-	languageChoices []string
+	languageChoices []choice
+	policyChoices   []choice
+	styleChoices    []choice
 	formatChoices   []string
 	config          *Config
 	outputs         []string
 	lastClipboard   string
 	lastProcessed   string
 	scrollPosition  int
-	processContent  func(string, string, bool) (string, error)
+	processContent  func(string, *Config) (Result, error)
 }
 
 type ClipboardUpdateMsg string
 type ErrorMsg error
 
-func initialModel(processContentFn func(string, string, bool) (string, error)) Model {
+func initialModel(processContentFn func(string, *Config) (Result, error)) Model {
 	return Model{
-		screen: languageSelect,
-		languageChoices: []string{
-			"Go",
-			"C/C++",
-			"Java",
-			"Python",
-			"JavaScript",
-			"JSX",
+		screen:          languageSelect,
+		languageChoices: languageChoices(),
+		policyChoices: []choice{
+			{name: "Remove all comments", value: "all"},
+			{name: "Keep doc comments", value: "keep-docs"},
+			{name: "Remove trailing inline comments only", value: "inline"},
+			{name: "Remove TODO/FIXME/HACK/XXX comments only", value: "matching"},
 		},
+		styleChoices: styleChoices(),
 		formatChoices: []string{
 			"Yes",
 			"No",
 		},
 		config: &Config{
-			Language: "go",
-			Format:   false,
+			Language:      "go",
+			Policy:        "all",
+			Format:        false,
+			MinConfidence: commentremover.DefaultMinConfidence,
 		},
 		outputs:        []string{},
 		processContent: processContentFn,
 	}
 }
 
+func languageChoices() []choice {
+	choices := []choice{{name: "Auto-detect", value: languages.Auto}}
+	for _, lang := range languages.All() {
+		choices = append(choices, choice{name: lang.Title, value: lang.Name})
+	}
+	return choices
+}
+
+func styleChoices() []choice {
+	choices := []choice{{name: "Remove comments"}}
+	for _, lang := range languages.All() {
+		choices = append(choices, choice{name: "Convert to " + lang.Title + " comments", value: lang.Name})
+	}
+	return choices
+}
+
 func (m Model) GetCurrentConfig() *Config {
 	return m.config
 }
+
+func (m Model) languageCursor() int {
+	for i, lang := range m.languageChoices {
+		if lang.value == m.config.Language {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m Model) policyCursor() int {
+	for i, policy := range m.policyChoices {
+		if policy.value == m.config.Policy {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m Model) styleCursor() int {
+	for i, style := range m.styleChoices {
+		if style.value == m.config.ConvertTo {
+			return i
+		}
+	}
+	return 0
+}
@@ -4,7 +4,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func NewProgram(processContentFn func(string, string, bool) (string, error)) *tea.Program {
+func NewProgram(processContentFn func(string, *Config) (Result, error)) *tea.Program {
 	return tea.NewProgram(initialModel(processContentFn))
 }
 
@@ -22,6 +22,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "m":
+			if m.screen == monitoring {
+				m.config.Markdown = !m.config.Markdown
+			}
+			return m, nil
+
+		case "e":
+			if m.screen == monitoring {
+				m.config.Extract = !m.config.Extract
+			}
+			return m, nil
+
 		case "esc":
 			if m.screen == contentView {
 				m.screen = monitoring
@@ -29,79 +41,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "backspace":
-			if m.screen == formatSelect {
+			if m.screen == policySelect {
 				m.screen = languageSelect
-				m.cursor = 0
-				for i, lang := range m.languageChoices {
-					switch m.config.Language {
-					case "go":
-						if lang == "Go" {
-							m.cursor = i
-						}
-					case "c":
-						if lang == "C/C++" {
-							m.cursor = i
-						}
-					case "java":
-						if lang == "Java" {
-							m.cursor = i
-						}
-					case "python":
-						if lang == "Python" {
-							m.cursor = i
-						}
-					case "javascript":
-						if lang == "JavaScript" {
-							m.cursor = i
-						}
-					case "jsx":
-						if lang == "JSX" {
-							m.cursor = i
-						}
-					}
-				}
+				m.cursor = m.languageCursor()
+			} else if m.screen == styleSelect {
+				m.screen = policySelect
+				m.cursor = m.policyCursor()
+			} else if m.screen == formatSelect {
+				m.screen = styleSelect
+				m.cursor = m.styleCursor()
 			}
 			return m, nil
 
 		case "s":
 			if m.screen == monitoring {
 				m.screen = languageSelect
-				m.cursor = 0
-				for i, lang := range m.languageChoices {
-					switch m.config.Language {
-					case "go":
-						if lang == "Go" {
-							m.cursor = i
-						}
-					case "c":
-						if lang == "C/C++" {
-							m.cursor = i
-						}
-					case "java":
-						if lang == "Java" {
-							m.cursor = i
-						}
-					case "python":
-						if lang == "Python" {
-							m.cursor = i
-						}
-					case "javascript":
-						if lang == "JavaScript" {
-							m.cursor = i
-						}
-					case "jsx":
-						if lang == "JSX" {
-							m.cursor = i
-						}
-					}
-				}
+				m.cursor = m.languageCursor()
 			}
 			return m, nil
 
 		case "up", "k":
 			if m.screen == contentView && m.scrollPosition > 0 {
 				m.scrollPosition--
-			} else if m.screen == languageSelect || m.screen == formatSelect {
+			} else if m.screen == languageSelect || m.screen == policySelect || m.screen == styleSelect || m.screen == formatSelect {
 				if m.cursor > 0 {
 					m.cursor--
 				}
@@ -119,6 +81,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			} else if m.screen == languageSelect && m.cursor < len(m.languageChoices)-1 {
 				m.cursor++
+			} else if m.screen == policySelect && m.cursor < len(m.policyChoices)-1 {
+				m.cursor++
+			} else if m.screen == styleSelect && m.cursor < len(m.styleChoices)-1 {
+				m.cursor++
 			} else if m.screen == formatSelect && m.cursor < len(m.formatChoices)-1 {
 				m.cursor++
 			}
@@ -126,20 +92,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter", " ":
 			if m.screen == languageSelect {
-				switch m.cursor {
-				case 0:
-					m.config.Language = "go"
-				case 1:
-					m.config.Language = "c"
-				case 2:
-					m.config.Language = "java"
-				case 3:
-					m.config.Language = "python"
-				case 4:
-					m.config.Language = "javascript"
-				case 5:
-					m.config.Language = "jsx"
-				}
+				m.config.Language = m.languageChoices[m.cursor].value
+
+				m.screen = policySelect
+				m.cursor = m.policyCursor()
+
+			} else if m.screen == policySelect {
+				m.config.Policy = m.policyChoices[m.cursor].value
+
+				m.screen = styleSelect
+				m.cursor = m.styleCursor()
+
+			} else if m.screen == styleSelect {
+				m.config.ConvertTo = m.styleChoices[m.cursor].value
 
 				m.screen = formatSelect
 				m.cursor = 0
@@ -165,7 +130,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if content != m.lastClipboard && content != "" {
 			m.lastClipboard = content
 
-			processed, err := m.processContent(content, m.config.Language, m.config.Format)
+			result, err := m.processContent(content, m.config)
+			for _, note := range result.Notes {
+				m.addToOutputsQueue(note)
+			}
+			processed := result.Content
 			if err != nil {
 				if strings.Contains(err.Error(), "formatter not found") {
 					m.config.Format = false
@@ -175,9 +144,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			if processed != content {
+			if processed == "" {
+				m.addToOutputsQueue("Warning: nothing left after processing, clipboard left unchanged")
+			} else if processed != content {
 				m.addToOutputsQueue("Processed clipboard content")
 				m.lastProcessed = processed
+				m.lastClipboard = processed
 				clipboard.Write(clipboard.FmtText, []byte(processed))
 			}
 		}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestToggleDoesNotReprocessOutput(t *testing.T) {
+	calls := 0
+	m := initialModel(func(content string, cfg *Config) (Result, error) {
+		calls++
+		return Result{Content: content}, nil
+	})
+	m.screen = monitoring
+	m.lastClipboard = "processed"
+
+	for _, key := range []string{"e", "m"} {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = model.(Model)
+	}
+	if !m.config.Extract || !m.config.Markdown {
+		t.Fatalf("Extract = %v, Markdown = %v; want both toggled on", m.config.Extract, m.config.Markdown)
+	}
+
+	model, _ := m.Update(ClipboardUpdateMsg("processed"))
+	m = model.(Model)
+	if calls != 0 {
+		t.Errorf("processContent called %d times for the tool's own output, want 0", calls)
+	}
+}
+
+func TestEmptyResultLeavesClipboard(t *testing.T) {
+	m := initialModel(func(content string, cfg *Config) (Result, error) {
+		return Result{}, nil
+	})
+	m.screen = monitoring
+
+	model, _ := m.Update(ClipboardUpdateMsg("// only comments"))
+	m = model.(Model)
+	if m.lastProcessed != "" {
+		t.Errorf("lastProcessed = %q, want the empty result to be discarded", m.lastProcessed)
+	}
+	if len(m.outputs) == 0 || m.outputs[len(m.outputs)-1] != "Warning: nothing left after processing, clipboard left unchanged" {
+		t.Errorf("outputs = %q, want a warning", m.outputs)
+	}
+}
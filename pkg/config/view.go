@@ -11,6 +11,10 @@ func (m Model) View() string {
 	switch m.screen {
 	case languageSelect:
 		return m.renderLanguageSelect()
+	case policySelect:
+		return m.renderPolicySelect()
+	case styleSelect:
+		return m.renderStyleSelect()
 	case formatSelect:
 		return m.renderFormatSelect()
 	case monitoring:
@@ -26,12 +30,119 @@ func (m Model) renderLanguageSelect() string {
 	title := titleStyle.Render(logo)
 	subtitle := subtitleStyle.Render("Select language for comment removal:")
 
+	listItems := renderScrollingChoices(m.languageChoices, m.cursor)
+
+	mutedInstructionStyle := buttonStyle
+	mutedInstructionStyle = mutedInstructionStyle.
+		Foreground(subtle).
+		Background(lipgloss.NoColor{}).
+		Bold(false)
+
+	instruction := mutedInstructionStyle.Render("[ Enter ] to select")
+	quitInstruction := mutedInstructionStyle.Render("[ q ] to quit")
+
+	instructions := lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		instruction,
+		"    ",
+		quitInstruction,
+	)
+
+	return appStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			subtitle,
+			listItems,
+			"",
+			instructions,
+		),
+	)
+}
+
+func renderScrollingChoices(choices []choice, cursor int) string {
+	const maxVisibleChoices = 12
+
+	startIdx := max(0, min(cursor-maxVisibleChoices/2, len(choices)-maxVisibleChoices))
+	endIdx := min(startIdx+maxVisibleChoices, len(choices))
+
+	moreStyle := listItemStyle.Foreground(subtle)
+
+	var listItems strings.Builder
+	if startIdx > 0 {
+		listItems.WriteString(moreStyle.Render("↑ more") + "\n")
+	}
+	for i, choice := range choices[startIdx:endIdx] {
+		if cursor == startIdx+i {
+			listItems.WriteString(selectedItemStyle.Render(choice.name) + "\n")
+		} else {
+			listItems.WriteString(listItemStyle.Render(choice.name) + "\n")
+		}
+	}
+	if endIdx < len(choices) {
+		listItems.WriteString(moreStyle.Render("↓ more") + "\n")
+	}
+	return listItems.String()
+}
+
+func (m Model) renderStyleSelect() string {
+	title := titleStyle.Render(logo)
+
+	langInfo := fmt.Sprintf("Selected language: %s\n",
+		highlightedInfoStyle.Render(m.config.Language))
+
+	subtitle := subtitleStyle.Render(
+		"Remove comments or convert them to another language's style?")
+
+	listItems := renderScrollingChoices(m.styleChoices, m.cursor)
+
+	mutedInstructionStyle := buttonStyle
+	mutedInstructionStyle = mutedInstructionStyle.
+		Foreground(subtle).
+		Background(lipgloss.NoColor{}).
+		Bold(false)
+
+	enterInstruction := mutedInstructionStyle.Render("[ Enter ] to select")
+	backInstruction := mutedInstructionStyle.Render("[ Backspace ] to go back")
+	quitInstruction := mutedInstructionStyle.Render("[ q ] to quit")
+
+	instructions := lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		enterInstruction,
+		"    ",
+		backInstruction,
+		"    ",
+		quitInstruction,
+	)
+
+	return appStyle.Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			langInfo,
+			subtitle,
+			listItems,
+			"",
+			instructions,
+		),
+	)
+}
+
+func (m Model) renderPolicySelect() string {
+	title := titleStyle.Render(logo)
+
+	langInfo := fmt.Sprintf("Selected language: %s\n",
+		highlightedInfoStyle.Render(m.config.Language))
+
+	subtitle := subtitleStyle.Render(
+		"Which comments should be removed?")
+
 	var listItems strings.Builder
-	for i, choice := range m.languageChoices {
+	for i, choice := range m.policyChoices {
 		if m.cursor == i {
-			listItems.WriteString(selectedItemStyle.Render(choice) + "\n")
+			listItems.WriteString(selectedItemStyle.Render(choice.name) + "\n")
 		} else {
-			listItems.WriteString(listItemStyle.Render(choice) + "\n")
+			listItems.WriteString(listItemStyle.Render(choice.name) + "\n")
 		}
 	}
 
@@ -41,12 +152,15 @@ func (m Model) renderLanguageSelect() string {
 		Background(lipgloss.NoColor{}).
 		Bold(false)
 
-	instruction := mutedInstructionStyle.Render("[ Enter ] to select")
+	enterInstruction := mutedInstructionStyle.Render("[ Enter ] to select")
+	backInstruction := mutedInstructionStyle.Render("[ Backspace ] to go back")
 	quitInstruction := mutedInstructionStyle.Render("[ q ] to quit")
 
 	instructions := lipgloss.JoinHorizontal(
 		lipgloss.Center,
-		instruction,
+		enterInstruction,
+		"    ",
+		backInstruction,
 		"    ",
 		quitInstruction,
 	)
@@ -55,6 +169,7 @@ func (m Model) renderLanguageSelect() string {
 		lipgloss.JoinVertical(
 			lipgloss.Left,
 			title,
+			langInfo,
 			subtitle,
 			listItems.String(),
 			"",
@@ -120,9 +235,25 @@ func (m Model) renderMonitoring() string {
 	langInfo := infoStyle.Render(fmt.Sprintf("Language: %s",
 		highlightedInfoStyle.Render(m.config.Language)))
 
+	policyInfo := infoStyle.Render(fmt.Sprintf("Removal: %s",
+		highlightedInfoStyle.Render(m.config.Policy)))
+
+	style := "remove"
+	if m.config.ConvertTo != "" {
+		style = "convert to " + m.config.ConvertTo
+	}
+	styleInfo := infoStyle.Render(fmt.Sprintf("Comments: %s",
+		highlightedInfoStyle.Render(style)))
+
 	formatInfo := infoStyle.Render(fmt.Sprintf("Autoformat: %s",
 		highlightedInfoStyle.Render(fmt.Sprintf("%v", m.config.Format))))
 
+	markdownInfo := infoStyle.Render(fmt.Sprintf("Markdown: %s",
+		highlightedInfoStyle.Render(fmt.Sprintf("%v", m.config.Markdown))))
+
+	extractInfo := infoStyle.Render(fmt.Sprintf("Copy only comments: %s",
+		highlightedInfoStyle.Render(fmt.Sprintf("%v", m.config.Extract))))
+
 	var logSection string
 	if len(m.outputs) == 0 {
 		logSection = infoStyle.Render("Waiting for clipboard content...")
@@ -161,6 +292,8 @@ func (m Model) renderMonitoring() string {
 
 	settingsInstruction := mutedInstructionStyle.Render("[ s ] to change settings")
 	viewInstruction := mutedInstructionStyle.Render("[ v ] to view last processed content")
+	markdownInstruction := mutedInstructionStyle.Render("[ m ] to toggle Markdown mode")
+	extractInstruction := mutedInstructionStyle.Render("[ e ] to toggle copying only comments")
 	quitInstruction := mutedInstructionStyle.Render("[ q ] to quit")
 
 	instructions := lipgloss.JoinHorizontal(
@@ -169,6 +302,10 @@ func (m Model) renderMonitoring() string {
 		"    ",
 		viewInstruction,
 		"    ",
+		markdownInstruction,
+		"    ",
+		extractInstruction,
+		"    ",
 		quitInstruction,
 	)
 
@@ -180,7 +317,11 @@ func (m Model) renderMonitoring() string {
 			lipgloss.JoinVertical(
 				lipgloss.Left,
 				langInfo,
+				policyInfo,
+				styleInfo,
 				formatInfo,
+				markdownInfo,
+				extractInfo,
 			),
 			"",
 			logSection,
@@ -0,0 +1,426 @@
+package languages
+
+var (
+	cBlock        = []Delimiter{{Open: "/*", Close: "*/"}}
+	doubleQuoted  = StringSyntax{Open: `"`, Close: `"`, Escape: `\`}
+	singleQuoted  = StringSyntax{Open: "'", Close: "'", Escape: `\`}
+	sqlQuoted     = StringSyntax{Open: "'", Close: "'", Doubled: true, Multiline: true}
+	jsStrings     = []StringSyntax{doubleQuoted, singleQuoted, {Open: "`", Close: "`", Escape: `\`, Multiline: true}}
+	jsPreserve    = []string{"license", "eslint", "ts-pragmas", "triple-slash"}
+	markup        = []Delimiter{{Open: "<!--", Close: "-->"}}
+	javadoc       = []string{"/**"}
+	cDocComments  = []string{"///", "//!", "/**", "/*!"}
+	shellPreserve = []string{"spdx", "shebang", "shellcheck"}
+	sqlPreserve   = []string{"spdx", "sql-hints"}
+	sqlfmt        = []string{"sqlfmt", "-"}
+	pgFormat      = []string{"pg_format", "-"}
+	sqlHelp       = "Install sqlfmt (pip install shandy-sqlfmt) or pgFormatter (https://github.com/darold/pgFormatter)"
+	prettierHelp  = "Install prettier: npm install -g prettier"
+)
+
+var builtins = []Language{
+	{
+		Name:          "go",
+		Title:         "Go",
+		Extensions:    []string{".go"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted, {Open: "`", Close: "`", Multiline: true}},
+		Preserve:      []string{"license", "nolint"},
+		Lexer:         "go",
+		Formatter:     []string{"gofmt"},
+	},
+	{
+		Name:          "c",
+		Title:         "C/C++",
+		Aliases:       []string{"cpp", "c++", "cc", "cxx", "h", "hpp", "objc"},
+		Extensions:    []string{".c", ".h", ".cpp", ".cc", ".cxx", ".hpp", ".hh", ".m", ".mm"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		DocComments:   cDocComments,
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:      []string{"license"},
+		Lexer:         "c",
+		Formatter:     []string{"clang-format"},
+		FormatterHelp: "Install clang-format: https://clang.llvm.org/docs/ClangFormat.html",
+	},
+	{
+		Name:               "java",
+		Title:              "Java",
+		Extensions:         []string{".java"},
+		LineComments:       []string{"//"},
+		BlockComments:      cBlock,
+		DocComments:        javadoc,
+		Strings:            []StringSyntax{{Open: `"""`, Close: `"""`, Escape: `\`, Multiline: true}, doubleQuoted, singleQuoted},
+		Preserve:           []string{"license"},
+		Lexer:              "java",
+		Formatter:          []string{"google-java-format", "-"},
+		FormatterFallbacks: [][]string{{"java", "-jar", "/usr/local/lib/google-java-format.jar", "-"}},
+		FormatterHelp:      "Install google-java-format: https://github.com/google/google-java-format",
+	},
+	{
+		Name:         "python",
+		Title:        "Python",
+		Aliases:      []string{"py"},
+		Extensions:   []string{".py", ".pyi", ".pyw"},
+		LineComments: []string{"#"},
+		Strings: []StringSyntax{
+			{Open: `"""`, Close: `"""`, Escape: `\`, Multiline: true},
+			{Open: "'''", Close: "'''", Escape: `\`, Multiline: true},
+			doubleQuoted,
+			singleQuoted,
+		},
+		Preserve:           []string{"license", "shebang", "encoding", "noqa", "type-ignore"},
+		Lexer:              "python",
+		Formatter:          []string{"black", "-", "-q"},
+		FormatterFallbacks: [][]string{{"python", "-m", "black", "-", "-q"}, {"python3", "-m", "black", "-", "-q"}},
+		FormatterHelp:      "Install black: pip install black",
+	},
+	{
+		Name:          "javascript",
+		Title:         "JavaScript",
+		Flag:          "js",
+		Aliases:       []string{"js"},
+		Extensions:    []string{".js", ".mjs", ".cjs"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		DocComments:   javadoc,
+		Strings:       jsStrings,
+		Preserve:      jsPreserve,
+		Lexer:         "javascript",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "babel"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "jsx",
+		Title:         "JSX",
+		Extensions:    []string{".jsx"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		DocComments:   javadoc,
+		Strings:       jsStrings,
+		Preserve:      jsPreserve,
+		Lexer:         "javascript",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "babel"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "typescript",
+		Title:         "TypeScript",
+		Flag:          "ts",
+		Aliases:       []string{"ts"},
+		Extensions:    []string{".ts", ".mts", ".cts"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		DocComments:   javadoc,
+		Strings:       jsStrings,
+		Preserve:      jsPreserve,
+		Lexer:         "typescript",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "typescript"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "tsx",
+		Title:         "TSX",
+		Extensions:    []string{".tsx"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		DocComments:   javadoc,
+		Strings:       jsStrings,
+		Preserve:      jsPreserve,
+		Lexer:         "tsx",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "typescript"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:           "rust",
+		Title:          "Rust",
+		Aliases:        []string{"rs"},
+		Extensions:     []string{".rs"},
+		LineComments:   []string{"//"},
+		BlockComments:  cBlock,
+		NestedComments: true,
+		DocComments:    cDocComments,
+		Strings:        []StringSyntax{{Open: `"`, Close: `"`, Escape: `\`, Multiline: true}},
+		Preserve:       []string{"spdx"},
+		Lexer:          "rust",
+		Formatter:      []string{"rustfmt", "--edition", "2021", "--emit", "stdout"},
+		FormatterHelp:  "Install rustfmt: rustup component add rustfmt",
+	},
+	{
+		Name:           "swift",
+		Title:          "Swift",
+		Extensions:     []string{".swift"},
+		LineComments:   []string{"//"},
+		BlockComments:  cBlock,
+		NestedComments: true,
+		DocComments:    []string{"///", "/**"},
+		Strings:        []StringSyntax{{Open: `"""`, Close: `"""`, Escape: `\`, Multiline: true}, doubleQuoted},
+		Lexer:          "swift",
+		Formatter:      []string{"swift-format"},
+		FormatterHelp:  "Install swift-format: https://github.com/swiftlang/swift-format",
+	},
+	{
+		Name:           "kotlin",
+		Title:          "Kotlin",
+		Aliases:        []string{"kt", "kts"},
+		Extensions:     []string{".kt", ".kts"},
+		LineComments:   []string{"//"},
+		BlockComments:  cBlock,
+		NestedComments: true,
+		DocComments:    javadoc,
+		Strings:        []StringSyntax{{Open: `"""`, Close: `"""`, Multiline: true}, doubleQuoted, singleQuoted},
+		Preserve:       []string{"license"},
+		Lexer:          "kotlin",
+		Formatter:      []string{"ktlint", "--stdin", "--format", "--log-level=none"},
+		FormatterHelp:  "Install ktlint: https://pinterest.github.io/ktlint/",
+	},
+	{
+		Name:           "scala",
+		Title:          "Scala",
+		Extensions:     []string{".scala", ".sc"},
+		LineComments:   []string{"//"},
+		BlockComments:  cBlock,
+		NestedComments: true,
+		DocComments:    javadoc,
+		Strings:        []StringSyntax{{Open: `"""`, Close: `"""`, Multiline: true}, doubleQuoted, singleQuoted},
+		Lexer:          "scala",
+		Formatter:      []string{"scalafmt", "--stdin", "--quiet"},
+		FormatterHelp:  "Install scalafmt: https://scalameta.org/scalafmt/",
+	},
+	{
+		Name:          "bash",
+		Title:         "Shell",
+		Aliases:       []string{"sh", "zsh", "shell"},
+		Extensions:    []string{".sh", ".bash", ".zsh"},
+		LineComments:  []string{"#"},
+		Strings:       []StringSyntax{{Open: `"`, Close: `"`, Escape: `\`, Multiline: true}, {Open: "'", Close: "'", Multiline: true}},
+		Preserve:      shellPreserve,
+		Lexer:         "bash",
+		Formatter:     []string{"shfmt"},
+		FormatterHelp: "Install shfmt: https://github.com/mvdan/sh",
+	},
+	{
+		Name:          "ruby",
+		Title:         "Ruby",
+		Aliases:       []string{"rb"},
+		Extensions:    []string{".rb", ".rake", ".gemspec"},
+		LineComments:  []string{"#"},
+		BlockComments: []Delimiter{{Open: "=begin", Close: "=end"}},
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:      []string{"spdx", "shebang", "magic-comments", "rubocop"},
+		Lexer:         "ruby",
+	},
+	{
+		Name:          "perl",
+		Title:         "Perl",
+		Aliases:       []string{"pl"},
+		Extensions:    []string{".pl", ".pm", ".t"},
+		LineComments:  []string{"#"},
+		BlockComments: []Delimiter{{Open: "=pod", Close: "=cut"}},
+		DocComments:   []string{"=pod"},
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:      []string{"spdx", "shebang"},
+		Lexer:         "perl",
+		Formatter:     []string{"perltidy", "-st", "-q"},
+		FormatterHelp: "Install perltidy: cpan Perl::Tidy",
+	},
+	{
+		Name:          "yaml",
+		Title:         "YAML",
+		Aliases:       []string{"yml"},
+		Extensions:    []string{".yaml", ".yml"},
+		LineComments:  []string{"#"},
+		Strings:       []StringSyntax{doubleQuoted, {Open: "'", Close: "'", Doubled: true}},
+		Preserve:      []string{"spdx", "schema"},
+		Lexer:         "yaml",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "yaml"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:         "toml",
+		Title:        "TOML",
+		Extensions:   []string{".toml"},
+		LineComments: []string{"#"},
+		Strings: []StringSyntax{
+			{Open: `"""`, Close: `"""`, Escape: `\`, Multiline: true},
+			{Open: "'''", Close: "'''", Multiline: true},
+			doubleQuoted,
+			{Open: "'", Close: "'"},
+		},
+		Preserve:      []string{"spdx", "schema"},
+		Lexer:         "toml",
+		Formatter:     []string{"taplo", "fmt", "-"},
+		FormatterHelp: "Install taplo: https://taplo.tamasfe.dev/",
+	},
+	{
+		Name:         "dockerfile",
+		Title:        "Dockerfile",
+		Aliases:      []string{"docker", "containerfile"},
+		Extensions:   []string{".dockerfile", "dockerfile", "containerfile"},
+		LineComments: []string{"#"},
+		Strings:      []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:     []string{"spdx", "dockerfile-directives"},
+		Lexer:        "dockerfile",
+	},
+	{
+		Name:         "makefile",
+		Title:        "Makefile",
+		Aliases:      []string{"make", "mk"},
+		Extensions:   []string{".mk", "makefile", "gnumakefile"},
+		LineComments: []string{"#"},
+		Preserve:     []string{"spdx"},
+		Lexer:        "makefile",
+	},
+	{
+		Name:               "sql",
+		Title:              "SQL",
+		Extensions:         []string{".sql"},
+		LineComments:       []string{"--"},
+		BlockComments:      cBlock,
+		Strings:            []StringSyntax{sqlQuoted, {Open: `"`, Close: `"`, Doubled: true, Multiline: true}},
+		Preserve:           sqlPreserve,
+		Lexer:              "sql",
+		Formatter:          sqlfmt,
+		FormatterFallbacks: [][]string{pgFormat},
+		FormatterHelp:      sqlHelp,
+	},
+	{
+		Name:               "mysql",
+		Title:              "SQL (MySQL)",
+		LineComments:       []string{"-- ", "#"},
+		BlockComments:      cBlock,
+		Strings:            []StringSyntax{{Open: "'", Close: "'", Escape: `\`, Doubled: true, Multiline: true}, {Open: `"`, Close: `"`, Escape: `\`, Doubled: true, Multiline: true}, {Open: "`", Close: "`", Doubled: true, Multiline: true}},
+		Preserve:           sqlPreserve,
+		Lexer:              "mysql",
+		Formatter:          sqlfmt,
+		FormatterFallbacks: [][]string{pgFormat},
+		FormatterHelp:      sqlHelp,
+	},
+	{
+		Name:               "postgresql",
+		Title:              "SQL (PostgreSQL)",
+		Flag:               "postgres",
+		Aliases:            []string{"postgres", "pgsql"},
+		Extensions:         []string{".pgsql", ".psql"},
+		LineComments:       []string{"--"},
+		BlockComments:      cBlock,
+		NestedComments:     true,
+		Strings:            []StringSyntax{sqlQuoted, {Open: `"`, Close: `"`, Doubled: true, Multiline: true}},
+		Preserve:           sqlPreserve,
+		Lexer:              "postgresql",
+		Formatter:          pgFormat,
+		FormatterFallbacks: [][]string{sqlfmt},
+		FormatterHelp:      sqlHelp,
+	},
+	{
+		Name:          "lua",
+		Title:         "Lua",
+		Extensions:    []string{".lua"},
+		LineComments:  []string{"--"},
+		BlockComments: []Delimiter{{Open: "--[[", Close: "]]"}, {Open: "--[=[", Close: "]=]"}, {Open: "--[==[", Close: "]==]"}},
+		DocComments:   []string{"---"},
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted, {Open: "[[", Close: "]]", Multiline: true}, {Open: "[=[", Close: "]=]", Multiline: true}, {Open: "[==[", Close: "]==]", Multiline: true}},
+		Preserve:      []string{"spdx", "shebang"},
+		Lexer:         "lua",
+		Formatter:     []string{"stylua", "-"},
+		FormatterHelp: "Install stylua: cargo install stylua",
+	},
+	{
+		Name:           "haskell",
+		Title:          "Haskell",
+		Aliases:        []string{"hs"},
+		Extensions:     []string{".hs", ".lhs"},
+		LineComments:   []string{"--"},
+		BlockComments:  []Delimiter{{Open: "{-", Close: "-}"}},
+		NestedComments: true,
+		DocComments:    []string{"-- |", "-- ^", "--|", "--^", "{-|", "{- |", "{-^", "{- ^"},
+		Strings:        []StringSyntax{doubleQuoted},
+		Preserve:       []string{"spdx", "shebang", "haskell-pragmas"},
+		Lexer:          "haskell",
+		Formatter:      []string{"ormolu"},
+		FormatterHelp:  "Install ormolu: cabal install ormolu",
+	},
+	{
+		Name:          "html",
+		Title:         "HTML",
+		Aliases:       []string{"xhtml", "htm"},
+		Extensions:    []string{".html", ".htm", ".xhtml"},
+		BlockComments: markup,
+		Strings:       []StringSyntax{{Open: `"`, Close: `"`, Multiline: true}, {Open: "'", Close: "'", Multiline: true}},
+		Preserve:      []string{"license", "conditional-comments"},
+		Lexer:         "html",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "html"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "xml",
+		Title:         "XML",
+		Aliases:       []string{"svg"},
+		Extensions:    []string{".xml", ".svg", ".xsd", ".xsl", ".xslt", ".plist"},
+		BlockComments: markup,
+		Strings:       []StringSyntax{{Open: `"`, Close: `"`, Multiline: true}, {Open: "'", Close: "'", Multiline: true}},
+		Preserve:      []string{"spdx"},
+		Lexer:         "xml",
+		Formatter:     []string{"xmllint", "--format", "-"},
+		FormatterHelp: "Install xmllint: part of libxml2",
+	},
+	{
+		Name:          "vue",
+		Title:         "Vue",
+		Extensions:    []string{".vue"},
+		BlockComments: markup,
+		Strings:       []StringSyntax{{Open: `"`, Close: `"`, Multiline: true}, {Open: "'", Close: "'", Multiline: true}},
+		Preserve:      []string{"license", "eslint", "conditional-comments"},
+		Lexer:         "html",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "vue"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "svelte",
+		Title:         "Svelte",
+		Extensions:    []string{".svelte"},
+		BlockComments: markup,
+		Strings:       []StringSyntax{{Open: `"`, Close: `"`, Multiline: true}, {Open: "'", Close: "'", Multiline: true}},
+		Preserve:      []string{"license", "eslint", "conditional-comments"},
+		Lexer:         "html",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "svelte"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "css",
+		Title:         "CSS",
+		Extensions:    []string{".css"},
+		BlockComments: cBlock,
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:      []string{"license"},
+		Lexer:         "css",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "css"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "scss",
+		Title:         "SCSS",
+		Aliases:       []string{"sass"},
+		Extensions:    []string{".scss", ".sass"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:      []string{"license"},
+		Lexer:         "scss",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "scss"},
+		FormatterHelp: prettierHelp,
+	},
+	{
+		Name:          "less",
+		Title:         "Less",
+		Extensions:    []string{".less"},
+		LineComments:  []string{"//"},
+		BlockComments: cBlock,
+		Strings:       []StringSyntax{doubleQuoted, singleQuoted},
+		Preserve:      []string{"license"},
+		Lexer:         "less",
+		Formatter:     []string{"prettier", "--stdin", "--parser", "less"},
+		FormatterHelp: prettierHelp,
+	},
+}
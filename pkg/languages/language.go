@@ -0,0 +1,268 @@
+package languages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type Delimiter struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+type StringSyntax struct {
+	Open      string `json:"open"`
+	Close     string `json:"close"`
+	Escape    string `json:"escape,omitempty"`
+	Doubled   bool   `json:"doubled,omitempty"`
+	Multiline bool   `json:"multiline,omitempty"`
+}
+
+type Language struct {
+	Name               string         `json:"name"`
+	Title              string         `json:"title"`
+	Flag               string         `json:"flag,omitempty"`
+	Aliases            []string       `json:"aliases,omitempty"`
+	Extensions         []string       `json:"extensions,omitempty"`
+	LineComments       []string       `json:"lineComments,omitempty"`
+	BlockComments      []Delimiter    `json:"blockComments,omitempty"`
+	NestedComments     bool           `json:"nestedComments,omitempty"`
+	DocComments        []string       `json:"docComments,omitempty"`
+	Strings            []StringSyntax `json:"strings,omitempty"`
+	Preserve           []string       `json:"preserve,omitempty"`
+	Lexer              string         `json:"lexer,omitempty"`
+	Formatter          []string       `json:"formatter,omitempty"`
+	FormatterFallbacks [][]string     `json:"formatterFallbacks,omitempty"`
+	FormatterHelp      string         `json:"formatterHelp,omitempty"`
+	Builtin            bool           `json:"-"`
+}
+
+const Auto = "auto"
+
+type configFile struct {
+	Languages []Language          `json:"languages"`
+	Preserve  map[string][]string `json:"preserve,omitempty"`
+}
+
+var (
+	mu        sync.RWMutex
+	languages []*Language
+	byName    = map[string]*Language{}
+
+	preserveValidator func(rules []string) error
+)
+
+func init() {
+	for i := range builtins {
+		builtins[i].Builtin = true
+		if err := Register(builtins[i]); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func SetPreserveValidator(validate func(rules []string) error) {
+	preserveValidator = validate
+}
+
+func Register(lang Language) error {
+	lang.Name = strings.ToLower(strings.TrimSpace(lang.Name))
+	if lang.Name == "" {
+		return errors.New("language name is required")
+	}
+	if lang.Name == Auto || slices.Contains(lang.Aliases, Auto) {
+		return fmt.Errorf("%q is reserved for language detection", Auto)
+	}
+	if err := lang.validate(); err != nil {
+		return fmt.Errorf("language %q: %v", lang.Name, err)
+	}
+	if lang.Title == "" {
+		lang.Title = lang.Name
+	}
+	if lang.Flag == "" {
+		lang.Flag = lang.Name
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := append([]string{lang.Name}, lang.Aliases...)
+	for _, name := range names {
+		if existing, ok := byName[strings.ToLower(name)]; ok && (existing.Builtin || existing.Name != lang.Name) {
+			return fmt.Errorf("language %q is already registered", name)
+		}
+	}
+
+	registered := &lang
+	if i := indexOf(lang.Name); i != -1 {
+		for name, existing := range byName {
+			if existing == languages[i] {
+				delete(byName, name)
+			}
+		}
+		languages[i] = registered
+	} else {
+		languages = append(languages, registered)
+	}
+	for _, name := range names {
+		byName[strings.ToLower(name)] = registered
+	}
+	return nil
+}
+
+func (lang Language) validate() error {
+	for _, prefix := range append(slices.Clone(lang.LineComments), lang.DocComments...) {
+		if prefix == "" {
+			return errors.New("comment prefixes must not be empty")
+		}
+	}
+	for _, d := range lang.BlockComments {
+		if d.Open == "" || d.Close == "" {
+			return errors.New("block comments need both open and close")
+		}
+	}
+	for _, str := range lang.Strings {
+		if str.Open == "" || str.Close == "" {
+			return errors.New("strings need both open and close")
+		}
+		if len(str.Escape) > 1 {
+			return fmt.Errorf("string escape %q must be a single character", str.Escape)
+		}
+	}
+	for _, command := range lang.FormatterFallbacks {
+		if len(command) == 0 || len(lang.Formatter) == 0 {
+			return errors.New("formatter fallbacks need a formatter and a command each")
+		}
+	}
+	return validatePreserve(lang.Preserve)
+}
+
+func validatePreserve(rules []string) error {
+	if preserveValidator == nil {
+		return nil
+	}
+	return preserveValidator(rules)
+}
+
+func SetPreserve(name string, rules []string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	lang, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return fmt.Errorf("unknown language %q", name)
+	}
+	if err := validatePreserve(rules); err != nil {
+		return fmt.Errorf("language %q: %v", lang.Name, err)
+	}
+	lang.Preserve = slices.Clone(rules)
+	return nil
+}
+
+func indexOf(name string) int {
+	for i, lang := range languages {
+		if lang.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func Lookup(name string) (Language, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	lang, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Language{}, false
+	}
+	return *lang, true
+}
+
+func Canonical(name string) string {
+	if lang, ok := Lookup(name); ok {
+		return lang.Name
+	}
+	return name
+}
+
+func ByExtension(path string) (Language, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	base := strings.ToLower(filepath.Base(path))
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, lang := range languages {
+		for _, e := range lang.Extensions {
+			if e == ext || e == base {
+				return *lang, true
+			}
+		}
+	}
+	return Language{}, false
+}
+
+func All() []Language {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]Language, len(languages))
+	for i, lang := range languages {
+		all[i] = *lang
+	}
+	return all
+}
+
+func DefaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "coder-copy", "languages.json")
+}
+
+func LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file configFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	var errs []error
+	for _, lang := range file.Languages {
+		if err := Register(lang); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", path, err))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(file.Preserve)) {
+		if err := SetPreserve(name, file.Preserve[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: preserve: %v", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func LoadUserConfig() error {
+	path := DefaultConfigFile()
+	if path == "" {
+		return nil
+	}
+
+	err := LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
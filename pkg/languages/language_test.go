@@ -0,0 +1,28 @@
+package languages
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadFilePreserveOverride(t *testing.T) {
+	python, _ := Lookup("python")
+	defer SetPreserve("python", python.Preserve)
+
+	path := filepath.Join(t.TempDir(), "languages.json")
+	config := `{"preserve": {"py": ["noqa"], "klingon": ["shebang"]}}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), `unknown language "klingon"`) {
+		t.Errorf("LoadFile() = %v, want an unknown language error", err)
+	}
+	if lang, _ := Lookup("python"); !slices.Equal(lang.Preserve, []string{"noqa"}) {
+		t.Errorf("python preserve = %q, want [noqa]", lang.Preserve)
+	}
+}
@@ -2,14 +2,20 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 
 	codeformatter "github.com/Ross1116/coder-copy/pkg/code_formatter"
 	commentremover "github.com/Ross1116/coder-copy/pkg/comment_remover"
+	"github.com/Ross1116/coder-copy/pkg/config"
+	"github.com/Ross1116/coder-copy/pkg/languages"
 	"golang.design/x/clipboard"
 )
 
-func MonitorClipboard(language string, format bool) string {
+func MonitorClipboard(cfg *config.Config) string {
 	ctx := context.Background()
 	copied := clipboard.Watch(ctx, clipboard.FmtText)
 	var prevContent string
@@ -19,53 +25,162 @@ func MonitorClipboard(language string, format bool) string {
 
 		if currContent != prevContent {
 			fmt.Println(currContent)
-			processedContent, err := ProcessContent(currContent, language, format)
+			result, err := Process(currContent, cfg)
+			for _, note := range result.Notes {
+				fmt.Println(note)
+			}
 
 			if err != nil {
 				fmt.Printf("Warning: %v\n", err)
 			}
 
-			clipboard.Write(clipboard.FmtText, []byte(processedContent))
-			prevContent = currContent
+			if result.Content == "" {
+				fmt.Println("Warning: nothing left after processing, clipboard left unchanged")
+				prevContent = currContent
+				continue
+			}
+			if result.Content != currContent {
+				clipboard.Write(clipboard.FmtText, []byte(result.Content))
+			}
+			prevContent = result.Content
 		}
 	}
 
 	return prevContent
 }
 
-func ProcessContent(content, language string, format bool) (string, error) {
-	strippedContent := commentremover.CommentRemover(content, language)
+func ProcessContent(content string, cfg *config.Config) (string, error) {
+	result, err := Process(content, cfg)
+	return result.Content, err
+}
+
+func Process(content string, cfg *config.Config) (config.Result, error) {
+	if cfg.Markdown {
+		processed, detections, err := commentremover.TransformMarkdown(content, cfg.Language, cfg.MinConfidence, func(code, language string) (string, error) {
+			block := *cfg
+			block.Language = language
+			block.Markdown = false
+			result, err := Process(code, &block)
+			return result.Content, err
+		})
+		var notes []string
+		for _, d := range detections {
+			switch {
+			case d.Language == "":
+				notes = append(notes, fmt.Sprintf("Code block at line %d left unchanged (language not recognised)", d.Line))
+			case d.Confidence < cfg.MinConfidence:
+				notes = append(notes, fmt.Sprintf("Code block at line %d left unchanged (looks like %s but only %.0f%% confident)",
+					d.Line, languageTitle(d.Language), d.Confidence*100))
+			default:
+				notes = append(notes, fmt.Sprintf("Code block at line %d: detected %s (%.0f%% confidence)", d.Line, languageTitle(d.Language), d.Confidence*100))
+			}
+		}
+		return config.Result{Content: processed, Notes: notes}, err
+	}
+
+	language := cfg.Language
+	var notes []string
+	if language == languages.Auto {
+		detection := commentremover.DetectLanguage(content)
+		if detection.Language == "" {
+			return config.Result{Content: content}, errors.New("clipboard left unchanged (language not recognised)")
+		}
+		if detection.Confidence < cfg.MinConfidence {
+			return config.Result{Content: content}, fmt.Errorf("clipboard left unchanged (looks like %s but only %.0f%% confident)",
+				languageTitle(detection.Language), detection.Confidence*100)
+		}
+		language = detection.Language
+		notes = append(notes, fmt.Sprintf("Detected %s (%.0f%% confidence)", languageTitle(language), detection.Confidence*100))
+	}
+
+	opts, optsErr := removerOptions(cfg, language)
+	if cfg.Extract {
+		extract := commentremover.ExtractOptions{LineNumbers: cfg.LineNumbers, Declarations: cfg.Declarations}
+		comments, err := commentremover.Extract(content, opts, extract)
+		if comments == "" {
+			return config.Result{Content: content, Notes: notes}, errors.Join(optsErr, err, errors.New("clipboard left unchanged (no comments found)"))
+		}
+		return config.Result{Content: comments, Notes: notes}, errors.Join(optsErr, err)
+	}
+
+	var strippedContent string
+	var err error
+	if cfg.ConvertTo != "" {
+		strippedContent, err = commentremover.Convert(content, opts, cfg.ConvertTo)
+	} else {
+		var result commentremover.Result
+		result, err = commentremover.Remove(content, opts)
+		strippedContent = result.Code
+	}
+	var unterminated *commentremover.UnterminatedError
+	if errors.As(err, &unterminated) && cfg.RefuseTruncated {
+		return config.Result{Content: content, Notes: notes}, errors.Join(optsErr, fmt.Errorf("clipboard left unchanged (%s)", err.Error()))
+	}
+	optsErr = errors.Join(optsErr, err)
+
+	if !cfg.Format {
+		return config.Result{Content: strippedContent, Notes: notes}, optsErr
+	}
 
-	if !format {
-		return strippedContent, nil
+	formattedContent, err := codeformatter.FormatCode(strippedContent, codeformatter.Language(language))
+	if err != nil {
+		return config.Result{Content: strippedContent, Notes: notes}, errors.Join(optsErr, fmt.Errorf("comments removed but formatting skipped (%s)", err.Error()))
 	}
 
-	var lang codeformatter.Language
-	switch language {
-	case "go":
-		lang = codeformatter.Go
-	case "cpp", "c++", "c":
-		lang = codeformatter.CPP
-	case "java":
-		lang = codeformatter.Java
-	case "javascript", "js":
-		lang = codeformatter.JS
-	case "typescript", "ts":
-		lang = codeformatter.TS
-	case "jsx":
-		lang = codeformatter.JSX
-	case "tsx":
-		lang = codeformatter.TSX
-	case "python", "py":
-		lang = codeformatter.Python
-	default:
-		lang = codeformatter.Go
+	return config.Result{Content: formattedContent, Notes: notes}, optsErr
+}
+
+func languageTitle(name string) string {
+	if lang, ok := languages.Lookup(name); ok {
+		return lang.Title
+	}
+	return name
+}
+
+func removerOptions(cfg *config.Config, language string) (commentremover.Options, error) {
+	opts := commentremover.DefaultOptions(language)
+	opts.DropBareStrings = cfg.DropBareStrings
+	opts.StripDeadCode = cfg.StripDeadCode
+	opts.KeepLineNumbers = cfg.KeepLineNumbers
+
+	var errs []error
+	policy, err := commentremover.ParseRemovalPolicy(cfg.Policy)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("all comments removed (%s)", err.Error()))
 	}
+	opts.Policy = policy
 
-	formattedContent, err := codeformatter.FormatCode(strippedContent, lang)
+	whitespace, err := commentremover.ParseWhitespacePolicy(cfg.BlankLines)
 	if err != nil {
-		return strippedContent, fmt.Errorf("comments removed but formatting skipped (%s)", err.Error())
+		errs = append(errs, fmt.Errorf("blank lines preserved (%s)", err.Error()))
+	}
+	opts.Whitespace = whitespace
+
+	if cfg.PolicyPattern != "" {
+		pattern, err := regexp.Compile(cfg.PolicyPattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("default removal pattern used (%s)", err.Error()))
+		} else {
+			opts.Pattern = pattern
+		}
+	}
+
+	preserve := cfg.Preserve
+	for _, name := range slices.Sorted(maps.Keys(cfg.LanguagePreserve)) {
+		if _, ok := languages.Lookup(name); !ok {
+			errs = append(errs, fmt.Errorf("preserve rules for unknown language %q ignored", name))
+		} else if name == languages.Canonical(language) {
+			preserve = cfg.LanguagePreserve[name]
+		}
+	}
+	if preserve != nil {
+		rules, err := commentremover.ParsePreserveRules(preserve)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("default preserve rules used (%s)", err.Error()))
+		} else {
+			opts.Preserve = rules
+		}
 	}
 
-	return formattedContent, nil
+	return opts, errors.Join(errs...)
 }
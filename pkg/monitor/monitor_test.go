@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ross1116/coder-copy/pkg/config"
+)
+
+func TestProcessRefuseTruncated(t *testing.T) {
+	const input = "x := 1 // c\n/* open"
+
+	cfg := &config.Config{Language: "go", Policy: "all", RefuseTruncated: true}
+	result, err := Process(input, cfg)
+	if result.Content != input {
+		t.Errorf("Content = %q, want the input unchanged", result.Content)
+	}
+	if err == nil || !strings.Contains(err.Error(), "unterminated comment") {
+		t.Errorf("err = %v, want an unterminated comment warning", err)
+	}
+}
+
+func TestProcessKeepsWarningsWhenFormattingFails(t *testing.T) {
+	cfg := &config.Config{Language: "go", Policy: "all", Format: true}
+	_, err := Process("x := `abc", cfg)
+	if err == nil || !strings.Contains(err.Error(), "unterminated string") || !strings.Contains(err.Error(), "formatting skipped") {
+		t.Errorf("err = %v, want both the unterminated and the formatting warning", err)
+	}
+}
+
+func TestProcessMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config.Config
+		input string
+		want  string
+	}{
+		{
+			name:  "preserve rules",
+			cfg:   config.Config{Language: "javascript", Preserve: []string{"re:keep"}},
+			input: "# Title\n\n```js\na(); // keep me\nb(); // drop me\n```\n",
+			want:  "# Title\n\n```js\na(); // keep me\nb();\n```\n",
+		},
+		{
+			name:  "per-language preserve rules keep other defaults",
+			cfg:   config.Config{Language: "auto", MinConfidence: 0.5, LanguagePreserve: map[string][]string{"python": {"noqa"}}},
+			input: "```python\nimport os  # noqa\n#!/not/kept\n```\n\n```js\n/* eslint-disable */\nlet a = 1; // c\n```\n",
+			want:  "```python\nimport os  # noqa\n```\n\n```js\n/* eslint-disable */\nlet a = 1;\n```\n",
+		},
+		{
+			name:  "formatting",
+			cfg:   config.Config{Language: "go", Format: true},
+			input: "# Title\n\n```go\npackage main\nfunc  main( ) {} // c\n```\n",
+			want:  "# Title\n\n```go\npackage main\n\nfunc main() {}\n```\n",
+		},
+		{
+			name:  "extraction",
+			cfg:   config.Config{Language: "python", Extract: true},
+			input: "# Title\n\n```python\nx = 1  # one\n```\n",
+			want:  "# Title\n\n```python\n# one\n```\n",
+		},
+		{
+			name:  "conversion",
+			cfg:   config.Config{Language: "python", ConvertTo: "go"},
+			input: "# Title\n\n```python\n# one\nx = 1\n```\n",
+			want:  "# Title\n\n```python\n// one\nx = 1\n```\n",
+		},
+		{
+			name:  "min confidence",
+			cfg:   config.Config{Language: "auto", MinConfidence: 0.99},
+			input: "# Title\n\n```\nx = 1 # c\n```\n",
+			want:  "# Title\n\n```\nx = 1 # c\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Markdown = true
+			cfg.Policy = "all"
+			result, err := Process(tt.input, &cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Content != tt.want {
+				t.Errorf("Process(%q)\n got %q\nwant %q", tt.input, result.Content, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessUnknownPreserveLanguage(t *testing.T) {
+	cfg := &config.Config{Language: "go", Policy: "all", LanguagePreserve: map[string][]string{"klingon": {"shebang"}}}
+	result, err := Process("x := 1 // c\n", cfg)
+	if result.Content != "x := 1\n" {
+		t.Errorf("Content = %q, want comments removed", result.Content)
+	}
+	if err == nil || !strings.Contains(err.Error(), `unknown language "klingon"`) {
+		t.Errorf("err = %v, want an unknown language warning", err)
+	}
+}
+
+func TestProcessMarkdownNotes(t *testing.T) {
+	input := "```\npackage main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(1) } // c\n```\n\n```\nx = 1 # c\n```\n\n```\n???\n```\n"
+	cfg := &config.Config{Language: "auto", Policy: "all", Markdown: true, MinConfidence: 0.5}
+	result, err := Process(input, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"Code block at line 1: detected Go (",
+		"Code block at line 9 left unchanged (looks like",
+		"Code block at line 13 left unchanged (language not recognised)",
+	}
+	if len(result.Notes) != len(want) {
+		t.Fatalf("Notes = %q, want %d notes", result.Notes, len(want))
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(result.Notes[i], prefix) {
+			t.Errorf("Notes[%d] = %q, want prefix %q", i, result.Notes[i], prefix)
+		}
+	}
+}